@@ -0,0 +1,304 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/alertmanager"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// alertTimeLayout is the layout used for alert start and end times.
+const alertTimeLayout string = "2006-01-02 15:04:05 MST"
+
+// alertStatusIcons maps alert status values to the emoji displayed
+// alongside the status.
+var alertStatusIcons = map[string]string{
+	alertmanager.StatusFiring:   "🔥",
+	alertmanager.StatusResolved: "✅",
+}
+
+// newAlertmanagerMessage uses the Alertmanager notification provided by the
+// user to generate a new Microsoft Teams message containing a single
+// Adaptive Card. The card is composed of a summary of the notification, a
+// table listing each alert, the labels common to every alert and a section
+// for each alert with its remaining labels and links to the alert source and
+// a new Alertmanager silence. The message title provided by the user (if
+// any) takes precedence over the generated title.
+func newAlertmanagerMessage(cfg *config.Config) ([]preparedMessage, error) {
+	n, err := alertmanager.Parse(cfg.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	title := cfg.MessageTitle
+	if title == "" {
+		title = n.Title()
+	}
+
+	card, err := adaptivecard.NewTextBlockCard(alertmanagerSummary(n), title, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new card for Alertmanager notification: %w", err)
+	}
+
+	table, err := newAlertsTable(n.Alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := card.AddElement(false, table); err != nil {
+		return nil, fmt.Errorf("failed to add alerts table to card: %w", err)
+	}
+
+	if err := addFacts(&card, labelFacts(n.CommonLabels)); err != nil {
+		return nil, err
+	}
+
+	for i, alert := range n.Alerts {
+		alertContainer, err := newAlertContainer(n, alert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process alert %d: %w", i+1, err)
+		}
+
+		if err := card.AddContainer(false, alertContainer); err != nil {
+			return nil, fmt.Errorf("failed to add container for alert %d to card: %w", i+1, err)
+		}
+	}
+
+	// A severity level specified by the user takes precedence.
+	severity := cfg.Severity
+	if severity == "" {
+		severity = alertmanagerSeverity(n)
+	}
+
+	return prepareMessages(cfg, card, severity)
+}
+
+// alertmanagerSummary returns the message text for the given notification
+// composed of the common summary or description annotation (if present)
+// along with the number of firing and resolved alerts.
+func alertmanagerSummary(n alertmanager.Notification) string {
+	var summary strings.Builder
+
+	switch {
+	case n.CommonAnnotations["summary"] != "":
+		summary.WriteString(n.CommonAnnotations["summary"] + "\n\n")
+	case n.CommonAnnotations["description"] != "":
+		summary.WriteString(n.CommonAnnotations["description"] + "\n\n")
+	}
+
+	fmt.Fprintf(
+		&summary,
+		"**%d** firing, **%d** resolved",
+		len(n.Firing()),
+		len(n.Resolved()),
+	)
+
+	if n.TruncatedAlerts > 0 {
+		fmt.Fprintf(&summary, " (**%d** alerts not shown)", n.TruncatedAlerts)
+	}
+
+	return summary.String()
+}
+
+// alertmanagerSeverity returns the message severity for the given
+// notification. Resolved notifications are treated as ok, otherwise the
+// common severity label (if present) is used to select the severity level.
+func alertmanagerSeverity(n alertmanager.Notification) string {
+	if n.Status == alertmanager.StatusResolved {
+		return config.SeverityOK
+	}
+
+	return labelSeverity(n.CommonLabels)
+}
+
+// labelSeverity returns the message severity corresponding to the severity
+// label in the given set of labels. Alerts without a recognized severity
+// label are treated as critical.
+func labelSeverity(labels alertmanager.KV) string {
+	switch strings.ToLower(labels["severity"]) {
+	case "info", "informational", "none":
+		return config.SeverityInfo
+	case "warning", "warn", "minor":
+		return config.SeverityWarning
+	default:
+		return config.SeverityCritical
+	}
+}
+
+// alertStatus returns the given alert status prefixed with an icon.
+func alertStatus(status string) string {
+	display := strings.ToUpper(status)
+	if icon, ok := alertStatusIcons[status]; ok {
+		display = icon + " " + display
+	}
+
+	return display
+}
+
+// alertTime returns the given alert time formatted for display. An empty
+// string is returned for unset times.
+func alertTime(t time.Time) string {
+	if t.IsZero() || t.Year() <= 1 {
+		return ""
+	}
+
+	return t.UTC().Format(alertTimeLayout)
+}
+
+// newAlertsTable creates a table listing the status, name, summary and start
+// time of each given alert.
+func newAlertsTable(alerts []alertmanager.Alert) (adaptivecard.Element, error) {
+	rows := make([][]adaptivecard.TableCell, 0, len(alerts)+1)
+
+	header, err := adaptivecard.NewTableCellsWithTextBlock(
+		[]interface{}{"Status", "Alert", "Summary", "Started"},
+	)
+	if err != nil {
+		return adaptivecard.Element{}, fmt.Errorf("failed to create alerts table header: %w", err)
+	}
+	rows = append(rows, header)
+
+	for i, alert := range alerts {
+		cells, err := adaptivecard.NewTableCellsWithTextBlock([]interface{}{
+			alertStatus(alert.Status),
+			alert.Name(),
+			alert.Summary(),
+			alertTime(alert.StartsAt),
+		})
+		if err != nil {
+			return adaptivecard.Element{}, fmt.Errorf("failed to create alerts table row %d: %w", i+1, err)
+		}
+
+		for _, cell := range cells {
+			for _, item := range cell.Items {
+				item.Wrap = true
+			}
+		}
+
+		rows = append(rows, cells)
+	}
+
+	table, err := adaptivecard.NewTableFromTableCells(rows, 0, true, true)
+	if err != nil {
+		return adaptivecard.Element{}, fmt.Errorf("failed to create alerts table: %w", err)
+	}
+
+	return table, nil
+}
+
+// labelFacts returns the given labels as a sorted collection of facts.
+func labelFacts(labels alertmanager.KV) []config.Fact {
+	facts := make([]config.Fact, 0, len(labels))
+	for _, name := range labels.Names() {
+		if labels[name] == "" {
+			continue
+		}
+		facts = append(facts, config.Fact{Title: name, Value: labels[name]})
+	}
+
+	return facts
+}
+
+// alertLink is a link to a page related to an alert (e.g., the alert source).
+type alertLink struct {
+	url   string
+	title string
+}
+
+// newLabelFactSet creates a FactSet displaying the given facts (e.g., alert
+// labels).
+func newLabelFactSet(facts []config.Fact) (adaptivecard.Element, error) {
+	factSet := adaptivecard.NewFactSet()
+	for _, fact := range facts {
+		if err := factSet.AddFact(adaptivecard.Fact{Title: fact.Title, Value: fact.Value}); err != nil {
+			return adaptivecard.Element{}, fmt.Errorf("failed to process fact %q: %w", fact.Title, err)
+		}
+	}
+
+	return adaptivecard.Element(factSet), nil
+}
+
+// addLinkActions adds an action opening each given link to the alert
+// container. Links without a URL are skipped.
+func addLinkActions(alertContainer *adaptivecard.Container, links []alertLink) error {
+	actions := make([]adaptivecard.Action, 0, len(links))
+	for _, link := range links {
+		if link.url == "" {
+			continue
+		}
+
+		action, err := adaptivecard.NewActionOpenURL(link.url, link.title)
+		if err != nil {
+			return fmt.Errorf("failed to process %s link: %w", link.title, err)
+		}
+		actions = append(actions, action)
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	if err := alertContainer.AddAction(false, actions...); err != nil {
+		return fmt.Errorf("failed to add alert links: %w", err)
+	}
+
+	return nil
+}
+
+// newAlertContainer creates a container for the given alert composed of a
+// heading, the labels not shared with other alerts in the notification and
+// links to the alert source and (if firing) a new Alertmanager silence for
+// the alert.
+func newAlertContainer(n alertmanager.Notification, alert alertmanager.Alert) (adaptivecard.Container, error) {
+	alertContainer := adaptivecard.NewContainer()
+	alertContainer.Separator = true
+	alertContainer.Spacing = adaptivecard.SpacingMedium
+
+	heading := adaptivecard.NewTextBlock(
+		fmt.Sprintf("%s: %s", alertStatus(alert.Status), alert.Name()),
+		true,
+	)
+	heading.Weight = adaptivecard.WeightBolder
+
+	if err := alertContainer.AddElement(false, heading); err != nil {
+		return adaptivecard.Container{}, fmt.Errorf("failed to add alert heading: %w", err)
+	}
+
+	if facts := labelFacts(n.UniqueLabels(alert)); len(facts) > 0 {
+		factSet, err := newLabelFactSet(facts)
+		if err != nil {
+			return adaptivecard.Container{}, err
+		}
+
+		if err := alertContainer.AddElement(false, factSet); err != nil {
+			return adaptivecard.Container{}, fmt.Errorf("failed to add alert labels: %w", err)
+		}
+	}
+
+	// Silencing is only useful for alerts which are still firing.
+	var silenceURL string
+	if alert.Status == alertmanager.StatusFiring {
+		silenceURL = n.SilenceURL(alert)
+	}
+
+	links := []alertLink{
+		{url: alert.GeneratorURL, title: "Source"},
+		{url: silenceURL, title: "Silence"},
+	}
+
+	if err := addLinkActions(&alertContainer, links); err != nil {
+		return adaptivecard.Container{}, err
+	}
+
+	return alertContainer, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/alertmanager"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// testAlertmanagerNotification returns an Alertmanager notification with the
+// given notification status and a single alert with the given alert status.
+func testAlertmanagerNotification(status string, alertStatus string) string {
+	return `{
+	"version": "4",
+	"status": "` + status + `",
+	"receiver": "teams",
+	"groupLabels": {"alertname": "HighLatency", "service": "api"},
+	"commonLabels": {"alertname": "HighLatency", "service": "api", "severity": "warning"},
+	"commonAnnotations": {"summary": "Request latency is high"},
+	"externalURL": "https://alertmanager.example.com",
+	"alerts": [
+		{
+			"status": "` + alertStatus + `",
+			"labels": {"alertname": "HighLatency", "service": "api", "severity": "warning", "instance": "web01:9090"},
+			"annotations": {"summary": "Latency on web01"},
+			"startsAt": "2026-10-17T12:00:00Z",
+			"generatorURL": "https://prometheus.example.com/graph"
+		}
+	]
+}`
+}
+
+// actionTitles returns the titles of the actions in the action sets within
+// the given elements.
+func actionTitles(elements []adaptivecard.Element) []string {
+	var titles []string
+	for _, element := range elements {
+		if element.Type != adaptivecard.TypeElementActionSet {
+			continue
+		}
+		for _, action := range element.Actions {
+			titles = append(titles, action.Title)
+		}
+	}
+
+	return titles
+}
+
+func TestNewAlertContainer(t *testing.T) {
+	tests := map[string]struct {
+		alertStatus     string
+		externalURL     string
+		expectedHeading string
+		expectedActions []string
+	}{
+		"firing": {
+			alertStatus:     alertmanager.StatusFiring,
+			externalURL:     "https://alertmanager.example.com",
+			expectedHeading: "🔥 FIRING: HighLatency",
+			expectedActions: []string{"Source", "Silence"},
+		},
+		"firing without external URL": {
+			alertStatus:     alertmanager.StatusFiring,
+			expectedHeading: "🔥 FIRING: HighLatency",
+			expectedActions: []string{"Source"},
+		},
+		"resolved": {
+			alertStatus:     alertmanager.StatusResolved,
+			externalURL:     "https://alertmanager.example.com",
+			expectedHeading: "✅ RESOLVED: HighLatency",
+			expectedActions: []string{"Source"},
+		},
+		"unknown status": {
+			alertStatus:     "pending",
+			externalURL:     "https://alertmanager.example.com",
+			expectedHeading: "PENDING: HighLatency",
+			expectedActions: []string{"Source"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			alert := alertmanager.Alert{
+				Status:       tt.alertStatus,
+				Labels:       alertmanager.KV{"alertname": "HighLatency", "instance": "web01:9090"},
+				GeneratorURL: "https://prometheus.example.com/graph",
+			}
+
+			n := alertmanager.Notification{
+				CommonLabels: alertmanager.KV{"alertname": "HighLatency"},
+				ExternalURL:  tt.externalURL,
+				Alerts:       []alertmanager.Alert{alert},
+			}
+
+			alertContainer, err := newAlertContainer(n, alert)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			heading := alertContainer.Items[0]
+			if heading.Text != tt.expectedHeading || heading.Weight != adaptivecard.WeightBolder {
+				t.Errorf("got heading %q (weight %q); expected %q (weight %q)",
+					heading.Text, heading.Weight, tt.expectedHeading, adaptivecard.WeightBolder)
+			}
+
+			factSet := alertContainer.Items[1]
+			expectedFacts := []adaptivecard.Fact{{Title: "instance", Value: "web01:9090"}}
+			if factSet.Type != adaptivecard.TypeElementFactSet || !reflect.DeepEqual(factSet.Facts, expectedFacts) {
+				t.Errorf("got %+v; expected fact set with unique labels %v", factSet, expectedFacts)
+			}
+
+			if got := actionTitles(alertContainer.Items); !reflect.DeepEqual(got, tt.expectedActions) {
+				t.Errorf("got actions %v; expected %v", got, tt.expectedActions)
+			}
+		})
+	}
+}
+
+func TestNewAlertmanagerMessage(t *testing.T) {
+	tests := map[string]struct {
+		input              string
+		title              string
+		severity           string
+		expectedTitle      string
+		expectedStyle      string
+		expectedColor      string
+		expectedAlertTitle string
+	}{
+		"firing": {
+			input:              testAlertmanagerNotification(alertmanager.StatusFiring, alertmanager.StatusFiring),
+			expectedTitle:      "⚠️ [FIRING:1] HighLatency (api)",
+			expectedStyle:      adaptivecard.ContainerStyleWarning,
+			expectedColor:      adaptivecard.ColorWarning,
+			expectedAlertTitle: "🔥 FIRING: HighLatency",
+		},
+		"resolved": {
+			input:              testAlertmanagerNotification(alertmanager.StatusResolved, alertmanager.StatusResolved),
+			expectedTitle:      "✅ [RESOLVED] HighLatency (api)",
+			expectedStyle:      adaptivecard.ContainerStyleGood,
+			expectedColor:      adaptivecard.ColorGood,
+			expectedAlertTitle: "✅ RESOLVED: HighLatency",
+		},
+		"user title and severity": {
+			input:              testAlertmanagerNotification(alertmanager.StatusFiring, alertmanager.StatusFiring),
+			title:              "Latency",
+			severity:           config.SeverityCritical,
+			expectedTitle:      "🚨 Latency",
+			expectedStyle:      adaptivecard.ContainerStyleAttention,
+			expectedColor:      adaptivecard.ColorAttention,
+			expectedAlertTitle: "🔥 FIRING: HighLatency",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Config{
+				Input:                  []byte(tt.input),
+				MessageTitle:           tt.title,
+				Severity:               tt.severity,
+				DisableBrandingTrailer: true,
+			}
+
+			messages, err := newAlertmanagerMessage(cfg)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if len(messages) != 1 {
+				t.Fatalf("got %d messages; expected 1", len(messages))
+			}
+
+			var message adaptivecard.Message
+			if err := json.Unmarshal(messages[0].payload, &message); err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			body := message.Attachments[0].Content.Body
+			if len(body) != 1 || body[0].Type != adaptivecard.TypeElementContainer {
+				t.Fatalf("got %+v; expected body to be a single severity container", body)
+			}
+
+			severityContainer := body[0]
+			if severityContainer.Style != tt.expectedStyle {
+				t.Errorf("got container style %q; expected %q", severityContainer.Style, tt.expectedStyle)
+			}
+
+			title := severityContainer.Items[0]
+			if title.Text != tt.expectedTitle || title.Color != tt.expectedColor {
+				t.Errorf("got title %q (color %q); expected %q (color %q)",
+					title.Text, title.Color, tt.expectedTitle, tt.expectedColor)
+			}
+
+			var found bool
+			for _, item := range severityContainer.Items {
+				if item.Type == adaptivecard.TypeElementContainer && len(item.Items) > 0 &&
+					strings.HasSuffix(item.Items[0].Text, ": HighLatency") {
+					found = true
+					if item.Items[0].Text != tt.expectedAlertTitle {
+						t.Errorf("got alert heading %q; expected %q", item.Items[0].Text, tt.expectedAlertTitle)
+					}
+				}
+			}
+
+			if !found {
+				t.Errorf("got %+v; expected alert container", severityContainer.Items)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerSeverity(t *testing.T) {
+	tests := map[string]struct {
+		status   string
+		severity string
+		expected string
+	}{
+		"resolved":           {status: alertmanager.StatusResolved, severity: "critical", expected: config.SeverityOK},
+		"firing critical":    {status: alertmanager.StatusFiring, severity: "critical", expected: config.SeverityCritical},
+		"firing warning":     {status: alertmanager.StatusFiring, severity: "Warning", expected: config.SeverityWarning},
+		"firing info":        {status: alertmanager.StatusFiring, severity: "info", expected: config.SeverityInfo},
+		"firing no severity": {status: alertmanager.StatusFiring, expected: config.SeverityCritical},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := alertmanager.Notification{Status: tt.status, CommonLabels: alertmanager.KV{}}
+			if tt.severity != "" {
+				n.CommonLabels["severity"] = tt.severity
+			}
+
+			if got := alertmanagerSeverity(n); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
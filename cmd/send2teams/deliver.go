@@ -0,0 +1,309 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"sync"
+
+	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// preparedMessage is a validated Microsoft Teams message whose JSON payload
+// has already been generated. Unlike an adaptivecard.Message (whose
+// Prepare method updates shared state), a preparedMessage may be safely
+// delivered to multiple webhook URLs concurrently.
+type preparedMessage struct {
+	payload []byte
+}
+
+// newPreparedMessage validates and prepares the given message, retaining
+// the generated JSON payload for later delivery.
+func newPreparedMessage(message *adaptivecard.Message) (preparedMessage, error) {
+	if err := message.Validate(); err != nil {
+		return preparedMessage{}, fmt.Errorf("failed to validate message: %w", err)
+	}
+
+	if err := message.Prepare(); err != nil {
+		return preparedMessage{}, fmt.Errorf("failed to prepare message: %w", err)
+	}
+
+	payload, err := io.ReadAll(message.Payload())
+	if err != nil {
+		return preparedMessage{}, fmt.Errorf("failed to read prepared message payload: %w", err)
+	}
+
+	return preparedMessage{payload: payload}, nil
+}
+
+// Prepare is a NOOP; the payload was generated when the preparedMessage was
+// created.
+func (pm preparedMessage) Prepare() error {
+	return nil
+}
+
+// Validate asserts that the prepared payload is present. The message used
+// to generate the payload was validated when the preparedMessage was
+// created.
+func (pm preparedMessage) Validate() error {
+	if len(pm.payload) == 0 {
+		return fmt.Errorf("prepared message payload is empty")
+	}
+
+	return nil
+}
+
+// Payload returns a new reader for the prepared JSON payload.
+func (pm preparedMessage) Payload() io.Reader {
+	return bytes.NewReader(pm.payload)
+}
+
+// PrettyPrint returns a formatted JSON payload of the prepared message.
+func (pm preparedMessage) PrettyPrint() string {
+	var prettyJSON bytes.Buffer
+	_ = json.Indent(&prettyJSON, pm.payload, "", "\t")
+
+	return prettyJSON.String()
+}
+
+// deliveryResult is the outcome of delivering a message to a single webhook
+// URL.
+type deliveryResult struct {
+	// Err is the error (if any) encountered when delivering the message.
+	Err error
+
+	// Target is a human-readable label identifying the webhook URL.
+	Target string
+
+	// URL is the webhook URL the message was submitted to.
+	URL string
+
+	// Destination is the webhook URL as configured (e.g., encrypted or
+	// base64 encoded). Unlike URL, this value may be persisted.
+	Destination string
+
+	// Ignored indicates whether an invalid response from the remote
+	// endpoint was ignored as requested by the user.
+	Ignored bool
+}
+
+// deliveryResults is a collection of deliveryResult values.
+type deliveryResults []deliveryResult
+
+// Failed returns the number of unsuccessful deliveries.
+func (drs deliveryResults) Failed() int {
+	var failed int
+	for _, result := range drs {
+		if result.Err != nil && !result.Ignored {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// IsFailure indicates whether the delivery results are considered an overall
+// failure based on the given failure policy.
+func (drs deliveryResults) IsFailure(failurePolicy string) bool {
+	failed := drs.Failed()
+
+	switch failurePolicy {
+	case config.FailurePolicyAll:
+		return failed == len(drs)
+	default:
+		return failed > 0
+	}
+}
+
+// targetLabel returns a human-readable label for the given webhook URL
+// suitable for use in log messages.
+func targetLabel(index int, total int, webhookURL string) string {
+	label := fmt.Sprintf("target %d of %d", index+1, total)
+
+	if u, err := url.Parse(webhookURL); err == nil && u.Host != "" {
+		label = fmt.Sprintf("%s (%s)", label, u.Hostname())
+	}
+
+	return label
+}
+
+// deliver concurrently submits the given message to each of the specified
+// webhook URLs using the provided Microsoft Teams client, retrying
+// submission as needed up to the configured number of retry attempts.
+// Results are returned in the same order as the given webhook URLs.
+func deliver(
+	ctx context.Context,
+	mstClient *goteamsnotify.TeamsClient,
+	cfg *config.Config,
+	webhookURLs []string,
+	message preparedMessage,
+) deliveryResults {
+	results := make(deliveryResults, len(webhookURLs))
+
+	var wg sync.WaitGroup
+	for i, webhookURL := range webhookURLs {
+		wg.Add(1)
+		go func(i int, webhookURL string) {
+			defer wg.Done()
+
+			sendErr := mstClient.SendWithRetry(ctx, webhookURL, message, cfg.Retries, cfg.RetriesDelay)
+
+			results[i] = deliveryResult{
+				Target:  targetLabel(i, len(webhookURLs), webhookURL),
+				URL:     webhookURL,
+				Err:     sendErr,
+				Ignored: cfg.IgnoreInvalidResponse && errors.Is(sendErr, goteamsnotify.ErrInvalidWebhookURLResponseText),
+			}
+		}(i, webhookURL)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// sendMessages submits the prepared messages in order to each configured
+// webhook URL using deliverMessages.
+func sendMessages(cfg *config.Config, messages []preparedMessage) error {
+	_, err := deliverMessages(context.Background(), newTeamsClient(cfg), cfg, messages)
+
+	return err
+}
+
+// deliverMessages submits the prepared messages in order to each configured
+// webhook URL within the configured timeout (applied to each message) and
+// logs the results. If a spool directory is configured, messages which could
+// not be delivered to a webhook URL are written to the spool directory along
+// with any later messages for the same webhook URL (so that messages split
+// from an oversized message are delivered in order). An error is returned if
+// delivery of a message is considered a failure based on the configured
+// failure policy and the failed deliveries were not spooled; submission
+// stops at that message. Whether any message was spooled instead of being
+// delivered is also returned.
+func deliverMessages(
+	ctx context.Context,
+	mstClient *goteamsnotify.TeamsClient,
+	cfg *config.Config,
+	messages []preparedMessage,
+) (bool, error) {
+	configured := cfg.ConfiguredWebhookURLs()
+	decoded := cfg.WebhookURLs()
+
+	// Webhook URLs (as configured) for which an earlier message was spooled.
+	spooled := make(map[string]bool)
+
+	for i, prepared := range messages {
+		destinations := make([]string, 0, len(configured))
+		webhookURLs := make([]string, 0, len(decoded))
+		for j, destination := range configured {
+			if !spooled[destination] {
+				destinations = append(destinations, destination)
+				webhookURLs = append(webhookURLs, decoded[j])
+				continue
+			}
+
+			if err := spoolMessage(cfg, destination, decoded[j], prepared, nil); err != nil {
+				return len(spooled) > 0, err
+			}
+		}
+
+		msgCtx, cancel := context.WithTimeout(ctx, cfg.TeamsSubmissionTimeout())
+		results := deliver(msgCtx, mstClient, cfg, webhookURLs, prepared)
+		cancel()
+
+		// Results are returned in the same order as the given webhook URLs.
+		for j := range results {
+			results[j].Destination = destinations[j]
+		}
+
+		logDeliveryResults(cfg, results)
+
+		if cfg.SpoolDir != "" {
+			if err := spoolFailedDeliveries(cfg, results, prepared); err != nil {
+				return len(spooled) > 0, err
+			}
+
+			for _, result := range results {
+				if result.Err != nil && !result.Ignored {
+					spooled[result.Destination] = true
+				}
+			}
+
+			continue
+		}
+
+		if results.IsFailure(cfg.FailurePolicy) {
+			err := fmt.Errorf(
+				"failed to deliver message to %d of %d targets (failure policy: %s)",
+				results.Failed(),
+				len(results),
+				cfg.FailurePolicy,
+			)
+
+			if len(messages) > 1 {
+				return false, fmt.Errorf("message %d of %d: %w", i+1, len(messages), err)
+			}
+
+			return false, err
+		}
+	}
+
+	return len(spooled) > 0, nil
+}
+
+// newTeamsClient creates a Microsoft Teams client using the given
+// configuration.
+func newTeamsClient(cfg *config.Config) *goteamsnotify.TeamsClient {
+	mstClient := goteamsnotify.NewTeamsClient()
+
+	// Override User Agent.
+	mstClient.SetUserAgent(cfg.UserAgent())
+
+	// Disable webhook URL validation if requested by user.
+	mstClient.SkipWebhookURLValidationOnSend(cfg.DisableWebhookURLValidation)
+
+	return mstClient
+}
+
+// logDeliveryResults emits the outcome of each delivery attempt unless
+// silent output was requested.
+func logDeliveryResults(cfg *config.Config, results deliveryResults) {
+	if cfg.SilentOutput {
+		return
+	}
+
+	for _, result := range results {
+		switch {
+
+		case result.Ignored:
+			log.Printf(
+				"WARNING: invalid response received from %s endpoint", result.Target)
+			log.Printf("ignoring error response as requested: \n%s", cfg.RedactError(result.Err))
+
+		// If an error occurred and we were not expecting one.
+		case result.Err != nil:
+			log.Printf("\n\nERROR: Failed to submit message to %q channel in the %q team via %s: %v\n\n",
+				cfg.Channel, cfg.Team, result.Target, cfg.RedactError(result.Err))
+
+		// Emit basic success message
+		case len(results) > 1:
+			log.Printf("Message successfully sent to %s!", result.Target)
+
+		default:
+			log.Println("Message successfully sent!")
+		}
+	}
+}
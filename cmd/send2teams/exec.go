@@ -0,0 +1,443 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// execOutputLimit is the maximum number of bytes of (combined) output from
+// the wrapped command included in the message. Earlier output is omitted.
+const execOutputLimit int = 8 * 1024
+
+// execNotFoundExitCode is the exit code used when the wrapped command could
+// not be started, matching the exit code used by common shells.
+const execNotFoundExitCode int = 127
+
+// Results of running the wrapped command recorded in the state file.
+const (
+	execStateSuccess string = "success"
+	execStateFailure string = "failure"
+)
+
+// execResult is the outcome of running the wrapped command.
+type execResult struct {
+	// Command is the wrapped command along with any arguments.
+	Command []string
+
+	// Host is the name of the host on which the command was run.
+	Host string
+
+	// Started is the time that the command was started.
+	Started time.Time
+
+	// Duration is the amount of time the command ran for.
+	Duration time.Duration
+
+	// ExitCode is the exit code of the command.
+	ExitCode int
+
+	// Err is the error encountered when starting or waiting for the command
+	// (if any). Non-zero exit codes are not recorded as errors.
+	Err error
+
+	// Output is the tail of the combined standard output and standard error
+	// of the command.
+	Output string
+
+	// OmittedBytes is the number of bytes of earlier output omitted from
+	// Output.
+	OmittedBytes int
+}
+
+// Succeeded indicates whether the command exited successfully.
+func (er execResult) Succeeded() bool {
+	return er.Err == nil && er.ExitCode == 0
+}
+
+// state returns the value recorded in the state file for the result.
+func (er execResult) state() string {
+	if er.Succeeded() {
+		return execStateSuccess
+	}
+
+	return execStateFailure
+}
+
+// commandLine returns the wrapped command and arguments as a single string.
+func (er execResult) commandLine() string {
+	return strings.Join(er.Command, " ")
+}
+
+// tailBuffer is an io.Writer which retains only the last limit bytes written
+// to it. It is safe for concurrent use.
+type tailBuffer struct {
+	mu      sync.Mutex
+	limit   int
+	buf     []byte
+	omitted int
+}
+
+// Write appends p to the buffer, discarding the oldest bytes once the limit
+// is exceeded.
+func (tb *tailBuffer) Write(p []byte) (int, error) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.buf = append(tb.buf, p...)
+	if excess := len(tb.buf) - tb.limit; excess > 0 {
+		tb.omitted += excess
+		tb.buf = append(tb.buf[:0], tb.buf[excess:]...)
+	}
+
+	return len(p), nil
+}
+
+// String returns the retained output. A partial multi-byte character at the
+// start of the retained output (left by discarding earlier bytes) is
+// removed.
+func (tb *tailBuffer) String() string {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	out := tb.buf
+	if tb.omitted > 0 {
+		for len(out) > 0 && !utf8.RuneStart(out[0]) {
+			out = out[1:]
+		}
+	}
+
+	return strings.ToValidUTF8(string(out), "�")
+}
+
+// runCommand runs the given command, passing through standard input and
+// output while capturing the tail of the combined output. Interrupt and
+// termination signals received while the command runs are forwarded to it.
+func runCommand(args []string) execResult {
+	result := execResult{
+		Command: args,
+		Started: time.Now(),
+	}
+
+	if host, err := os.Hostname(); err == nil {
+		result.Host = host
+	}
+
+	output := tailBuffer{limit: execOutputLimit}
+
+	// #nosec G204 -- running the user-specified command is the purpose of
+	// the exec command
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		result.Err = fmt.Errorf("failed to start command: %w", err)
+		result.ExitCode = execNotFoundExitCode
+
+		return result
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+
+	result.Duration = time.Since(result.Started)
+	result.Output = output.String()
+	result.OmittedBytes = output.omitted
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+
+		// Follow the shell convention for commands terminated by a signal.
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			result.ExitCode = 128 + int(status.Signal())
+		}
+
+	case err != nil:
+		result.Err = err
+		result.ExitCode = 1
+	}
+
+	return result
+}
+
+// defaultStateFile returns the path of the state file used for the given
+// command if a state file is not specified. The file is specific to the
+// command and arguments and is located in the user cache directory.
+func defaultStateFile(args []string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine state file location: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+
+	return filepath.Join(
+		cacheDir,
+		"send2teams",
+		"exec-"+hex.EncodeToString(sum[:8])+".state",
+	), nil
+}
+
+// readState returns the result recorded in the given state file. A missing
+// state file is treated as a previous success so that only failures are
+// reported for the first run.
+func readState(stateFile string) (string, error) {
+	// #nosec G304 -- file path is intentionally provided by the user
+	data, err := os.ReadFile(stateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return execStateSuccess, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeState records the given result in the state file. The file is
+// replaced atomically so that concurrent runs never observe a partial
+// write.
+func writeState(stateFile string, state string) error {
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0o700); err != nil {
+		return fmt.Errorf("failed to create state file directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(stateFile), filepath.Base(stateFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(state + "\n"); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), stateFile); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
+
+// shouldNotify indicates whether a message should be submitted for the
+// given result based on the notify-on policy. For the change policy the path
+// of the state file is also returned if the result differs from the
+// recorded result. The state file is not updated; the caller records the
+// result once the message has been delivered (or spooled) so that a change
+// which could not be reported is reported by the next run.
+func shouldNotify(cfg *config.Config, result execResult) (bool, string, error) {
+	switch cfg.NotifyOn {
+	case config.NotifyOnFailure:
+		return !result.Succeeded(), "", nil
+
+	case config.NotifyOnChange:
+		stateFile := cfg.StateFile
+		if stateFile == "" {
+			var err error
+			if stateFile, err = defaultStateFile(result.Command); err != nil {
+				return false, "", err
+			}
+		}
+
+		previous, err := readState(stateFile)
+		if err != nil {
+			return false, "", err
+		}
+
+		if previous == result.state() {
+			return false, "", nil
+		}
+
+		return true, stateFile, nil
+
+	default:
+		return true, "", nil
+	}
+}
+
+// runExec runs the wrapped command and (depending on the notify-on policy)
+// submits a message describing the result. The exit code of the wrapped
+// command is returned regardless of whether the message was submitted.
+func runExec(cfg *config.Config) int {
+	result := runCommand(cfg.Args)
+
+	if !cfg.SilentOutput {
+		switch {
+		case result.Err != nil:
+			log.Printf("Command %q failed: %v", result.commandLine(), result.Err)
+		case !result.Succeeded():
+			log.Printf("Command %q failed with exit status %d", result.commandLine(), result.ExitCode)
+		}
+	}
+
+	notify, stateFile, err := shouldNotify(cfg, result)
+	if err != nil {
+		// Report the result rather than silently dropping it.
+		notify = true
+		if !cfg.SilentOutput {
+			log.Printf("WARNING: Failed to determine previous result: %v", err)
+		}
+	}
+
+	if !notify {
+		if cfg.VerboseOutput {
+			log.Printf("Skipping message submission (notify-on: %s)", cfg.NotifyOn)
+		}
+
+		return result.ExitCode
+	}
+
+	if err := submitExecResult(cfg, result); err != nil {
+		if !cfg.SilentOutput {
+			log.Printf(
+				"\n\nERROR: Failed to submit result of command for %q channel in the %q team: %v\n\n",
+				cfg.Channel,
+				cfg.Team,
+				cfg.RedactError(err),
+			)
+		}
+
+		return result.ExitCode
+	}
+
+	// Record the result only once it has been reported.
+	if stateFile != "" && !cfg.DryRun {
+		if err := writeState(stateFile, result.state()); err != nil && !cfg.SilentOutput {
+			log.Printf("WARNING: Failed to record result: %v", err)
+		}
+	}
+
+	return result.ExitCode
+}
+
+// submitExecResult generates a message describing the given result of the
+// wrapped command and submits it to the configured webhook URLs (or emits
+// the payload if a dry-run was requested).
+func submitExecResult(cfg *config.Config, result execResult) error {
+	messages, err := newExecMessage(cfg, result)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		for _, prepared := range messages {
+			fmt.Println(prepared.PrettyPrint())
+		}
+		return nil
+	}
+
+	return sendMessages(cfg, messages)
+}
+
+// newExecMessage uses the given result of the wrapped command to generate a
+// new Microsoft Teams message containing a single Adaptive Card. The card is
+// composed of the message text provided by the user (if any), a summary of
+// the result, key details as facts and the tail of the command output. The
+// message title provided by the user (if any) is used in place of the
+// command. The message is styled based on the result unless a severity
+// level was specified by the user.
+func newExecMessage(cfg *config.Config, result execResult) ([]preparedMessage, error) {
+	title := cfg.MessageTitle
+	if title == "" {
+		title = result.commandLine()
+	}
+
+	outcome := "succeeded"
+	severity := config.SeverityOK
+	if !result.Succeeded() {
+		outcome = "failed"
+		severity = config.SeverityCritical
+	}
+
+	if cfg.Severity != "" {
+		severity = cfg.Severity
+	}
+
+	text := fmt.Sprintf("Command **%s** with exit status **%d**", outcome, result.ExitCode)
+	if result.Err != nil {
+		text += fmt.Sprintf(": %v", result.Err)
+	}
+
+	if cfg.MessageText != "" {
+		text = cfg.MessageText + "\n\n" + text
+	}
+
+	card, err := adaptivecard.NewTextBlockCard(text, title, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new card for command result: %w", err)
+	}
+
+	facts := []config.Fact{
+		{Title: "Command", Value: result.commandLine()},
+		{Title: "Exit status", Value: fmt.Sprintf("%d", result.ExitCode)},
+		{Title: "Duration", Value: result.Duration.Round(time.Millisecond).String()},
+		{Title: "Started", Value: result.Started.Format(time.RFC3339)},
+	}
+
+	if result.Host != "" {
+		facts = append(facts, config.Fact{Title: "Host", Value: result.Host})
+	}
+
+	if err := addFacts(&card, facts); err != nil {
+		return nil, err
+	}
+
+	if output := strings.TrimRight(result.Output, "\r\n"); output != "" {
+		if result.OmittedBytes > 0 {
+			output = fmt.Sprintf("[... %d earlier bytes omitted ...]\n%s", result.OmittedBytes, output)
+		}
+
+		if err := card.AddElement(false, adaptivecard.NewCodeBlock(output, "PlainText", 1)); err != nil {
+			return nil, fmt.Errorf("failed to add command output to card: %w", err)
+		}
+	}
+
+	return prepareMessages(cfg, card, severity)
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+	"github.com/atc0005/send2teams/internal/grafana"
+)
+
+// newGrafanaMessage uses the Grafana alerting notification provided by the
+// user to generate a new Microsoft Teams message containing a single
+// Adaptive Card. The card is composed of a summary of the notification, the
+// labels common to every alert and a section for each alert with its state,
+// query values, remaining labels, panel image (if available) and links to
+// the alert source and a new silence. Links to the dashboards and panels for
+// each alert are added as target URL "buttons" after any provided by the
+// user. The message title provided by the user (if any) takes precedence
+// over the title generated by Grafana.
+func newGrafanaMessage(cfg *config.Config) ([]preparedMessage, error) {
+	n, err := grafana.Parse(cfg.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	title := cfg.MessageTitle
+	if title == "" {
+		title = n.Title
+	}
+
+	card, err := adaptivecard.NewTextBlockCard(grafanaSummary(n), title, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new card for Grafana notification: %w", err)
+	}
+
+	if err := addFacts(&card, labelFacts(n.CommonLabels)); err != nil {
+		return nil, err
+	}
+
+	for i, alert := range n.Alerts {
+		alertContainer, err := newGrafanaAlertContainer(n, alert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process alert %d: %w", i+1, err)
+		}
+
+		if err := card.AddContainer(false, alertContainer); err != nil {
+			return nil, fmt.Errorf("failed to add container for alert %d to card: %w", i+1, err)
+		}
+	}
+
+	// Avoid modifying the target URLs for the original configuration when
+	// adding the dashboard and panel links.
+	msgCfg := *cfg
+	msgCfg.TargetURLs = append(
+		append([]config.TargetURL(nil), cfg.TargetURLs...),
+		grafanaTargetURLs(n)...,
+	)
+
+	// A severity level specified by the user takes precedence.
+	severity := cfg.Severity
+	if severity == "" {
+		severity = grafanaSeverity(n)
+	}
+
+	return prepareMessages(&msgCfg, card, severity)
+}
+
+// grafanaSummary returns the message text for the given notification
+// composed of the common summary or description annotation (if present)
+// along with the number of firing and resolved alerts.
+func grafanaSummary(n grafana.Notification) string {
+	var summary strings.Builder
+
+	switch {
+	case n.CommonAnnotations["summary"] != "":
+		summary.WriteString(n.CommonAnnotations["summary"] + "\n\n")
+	case n.CommonAnnotations["description"] != "":
+		summary.WriteString(n.CommonAnnotations["description"] + "\n\n")
+	}
+
+	firing := len(n.Firing())
+
+	fmt.Fprintf(
+		&summary,
+		"**%d** firing, **%d** resolved",
+		firing,
+		len(n.Alerts)-firing,
+	)
+
+	if n.TruncatedAlerts > 0 {
+		fmt.Fprintf(&summary, " (**%d** alerts not shown)", n.TruncatedAlerts)
+	}
+
+	return summary.String()
+}
+
+// grafanaSeverity returns the message severity for the given notification.
+// Resolved notifications are treated as ok, otherwise the common severity
+// label (if present) is used to select the severity level.
+func grafanaSeverity(n grafana.Notification) string {
+	if n.Status == grafana.StatusResolved {
+		return config.SeverityOK
+	}
+
+	return labelSeverity(n.CommonLabels)
+}
+
+// grafanaTargetURLs returns the unique dashboard and panel links for the
+// alerts in the given notification. If alerts link to more than one
+// dashboard or panel the alert name is included in the link description.
+func grafanaTargetURLs(n grafana.Notification) []config.TargetURL {
+	kinds := []struct {
+		desc   string
+		rawURL func(grafana.Alert) string
+	}{
+		{desc: "Dashboard", rawURL: func(a grafana.Alert) string { return a.DashboardURL }},
+		{desc: "Panel", rawURL: func(a grafana.Alert) string { return a.PanelURL }},
+	}
+
+	var targetURLs []config.TargetURL
+
+	for _, kind := range kinds {
+		seen := make(map[string]bool)
+		var links []config.TargetURL
+
+		for _, alert := range n.Alerts {
+			rawURL := kind.rawURL(alert)
+			if rawURL == "" || seen[rawURL] {
+				continue
+			}
+			seen[rawURL] = true
+
+			u, err := url.Parse(rawURL)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				continue
+			}
+
+			links = append(links, config.TargetURL{
+				URL:         *u,
+				Description: fmt.Sprintf("%s (%s)", kind.desc, alert.Name()),
+			})
+		}
+
+		if len(links) == 1 {
+			links[0].Description = kind.desc
+		}
+
+		targetURLs = append(targetURLs, links...)
+	}
+
+	return targetURLs
+}
+
+// newGrafanaAlertContainer creates a container for the given alert composed
+// of a heading, the summary (if present), the query values and labels not
+// shared with other alerts in the notification, the panel image (if
+// available) and links to the alert source and (if firing) a new silence for
+// the alert.
+func newGrafanaAlertContainer(n grafana.Notification, alert grafana.Alert) (adaptivecard.Container, error) {
+	alertContainer := adaptivecard.NewContainer()
+	alertContainer.Separator = true
+	alertContainer.Spacing = adaptivecard.SpacingMedium
+
+	heading := adaptivecard.NewTextBlock(
+		fmt.Sprintf("%s: %s", alertStatus(alert.Status), alert.Name()),
+		true,
+	)
+	heading.Weight = adaptivecard.WeightBolder
+
+	if err := alertContainer.AddElement(false, heading); err != nil {
+		return adaptivecard.Container{}, fmt.Errorf("failed to add alert heading: %w", err)
+	}
+
+	if summary := alert.Summary(); summary != "" {
+		if err := alertContainer.AddElement(false, adaptivecard.NewTextBlock(summary, true)); err != nil {
+			return adaptivecard.Container{}, fmt.Errorf("failed to add alert summary: %w", err)
+		}
+	}
+
+	facts := make([]config.Fact, 0, len(alert.Values)+len(alert.Labels)+1)
+	for _, value := range alert.FormattedValues() {
+		facts = append(facts, config.Fact{Title: value[0], Value: value[1]})
+	}
+
+	// Older Grafana releases only provide the formatted value string.
+	if len(alert.Values) == 0 && alert.ValueString != "" {
+		facts = append(facts, config.Fact{Title: "Values", Value: alert.ValueString})
+	}
+
+	facts = append(facts, labelFacts(n.UniqueLabels(alert))...)
+
+	if started := alertTime(alert.StartsAt); started != "" {
+		facts = append(facts, config.Fact{Title: "Started", Value: started})
+	}
+
+	if len(facts) > 0 {
+		factSet, err := newLabelFactSet(facts)
+		if err != nil {
+			return adaptivecard.Container{}, err
+		}
+
+		if err := alertContainer.AddElement(false, factSet); err != nil {
+			return adaptivecard.Container{}, fmt.Errorf("failed to add alert facts: %w", err)
+		}
+	}
+
+	if alert.ImageURL != "" {
+		image := adaptivecard.Element{
+			Type: adaptivecard.TypeElementImage,
+			URL:  alert.ImageURL,
+		}
+
+		if err := alertContainer.AddElement(false, image); err != nil {
+			return adaptivecard.Container{}, fmt.Errorf("failed to add alert panel image: %w", err)
+		}
+	}
+
+	// Silencing is only useful for alerts which are still firing.
+	var silenceURL string
+	if alert.Status == grafana.StatusFiring {
+		silenceURL = alert.SilenceURL
+	}
+
+	links := []alertLink{
+		{url: alert.GeneratorURL, title: "Source"},
+		{url: silenceURL, title: "Silence"},
+	}
+
+	if err := addLinkActions(&alertContainer, links); err != nil {
+		return adaptivecard.Container{}, err
+	}
+
+	return alertContainer, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/alertmanager"
+	"github.com/atc0005/send2teams/internal/grafana"
+)
+
+// testGrafanaAlert returns a Grafana alert with the given name and
+// dashboard and panel URLs.
+func testGrafanaAlert(name string, dashboardURL string, panelURL string) grafana.Alert {
+	return grafana.Alert{
+		Alert: alertmanager.Alert{
+			Status: grafana.StatusFiring,
+			Labels: alertmanager.KV{"alertname": name},
+		},
+		DashboardURL: dashboardURL,
+		PanelURL:     panelURL,
+	}
+}
+
+func TestGrafanaTargetURLs(t *testing.T) {
+	type link struct {
+		URL         string
+		Description string
+	}
+
+	tests := map[string]struct {
+		alerts   []grafana.Alert
+		expected []link
+	}{
+		"single dashboard and panel": {
+			alerts: []grafana.Alert{
+				testGrafanaAlert("HighCPU", "https://grafana.example.com/d/abc", "https://grafana.example.com/d/abc?viewPanel=2"),
+				testGrafanaAlert("HighCPU", "https://grafana.example.com/d/abc", "https://grafana.example.com/d/abc?viewPanel=2"),
+			},
+			expected: []link{
+				{URL: "https://grafana.example.com/d/abc", Description: "Dashboard"},
+				{URL: "https://grafana.example.com/d/abc?viewPanel=2", Description: "Panel"},
+			},
+		},
+		"multiple dashboards": {
+			alerts: []grafana.Alert{
+				testGrafanaAlert("HighCPU", "https://grafana.example.com/d/abc", "https://grafana.example.com/d/abc?viewPanel=2"),
+				testGrafanaAlert("DiskFull", "https://grafana.example.com/d/def", "https://grafana.example.com/d/abc?viewPanel=2"),
+			},
+			expected: []link{
+				{URL: "https://grafana.example.com/d/abc", Description: "Dashboard (HighCPU)"},
+				{URL: "https://grafana.example.com/d/def", Description: "Dashboard (DiskFull)"},
+				{URL: "https://grafana.example.com/d/abc?viewPanel=2", Description: "Panel"},
+			},
+		},
+		"missing and invalid URLs": {
+			alerts: []grafana.Alert{
+				testGrafanaAlert("HighCPU", "", "/d/abc?viewPanel=2"),
+				testGrafanaAlert("DiskFull", "https://grafana.example.com/d/def", "://invalid"),
+			},
+			expected: []link{
+				{URL: "https://grafana.example.com/d/def", Description: "Dashboard"},
+			},
+		},
+		"no URLs": {
+			alerts: []grafana.Alert{testGrafanaAlert("HighCPU", "", "")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []link
+			for _, targetURL := range grafanaTargetURLs(grafana.Notification{Alerts: tt.alerts}) {
+				got = append(got, link{URL: targetURL.URL.String(), Description: targetURL.Description})
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v; expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewGrafanaAlertContainer(t *testing.T) {
+	tests := map[string]struct {
+		status          string
+		imageURL        string
+		expectedImage   bool
+		expectedActions []string
+	}{
+		"firing with image": {
+			status:          grafana.StatusFiring,
+			imageURL:        "https://grafana.example.com/render/abc.png",
+			expectedImage:   true,
+			expectedActions: []string{"Source", "Silence"},
+		},
+		"firing without image": {
+			status:          grafana.StatusFiring,
+			expectedActions: []string{"Source", "Silence"},
+		},
+		"resolved with image": {
+			status:          grafana.StatusResolved,
+			imageURL:        "https://grafana.example.com/render/abc.png",
+			expectedImage:   true,
+			expectedActions: []string{"Source"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			alert := grafana.Alert{
+				Alert: alertmanager.Alert{
+					Status:       tt.status,
+					Labels:       alertmanager.KV{"alertname": "HighCPU", "instance": "web01"},
+					Annotations:  alertmanager.KV{"summary": "CPU on web01"},
+					GeneratorURL: "https://grafana.example.com/alerting/grafana/abc/view",
+				},
+				Values:     map[string]float64{"B": 95.5, "A": 0.955},
+				SilenceURL: "https://grafana.example.com/alerting/silence/new",
+				ImageURL:   tt.imageURL,
+			}
+
+			n := grafana.Notification{
+				CommonLabels: alertmanager.KV{"alertname": "HighCPU"},
+				Alerts:       []grafana.Alert{alert},
+			}
+
+			alertContainer, err := newGrafanaAlertContainer(n, alert)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			var (
+				images  []string
+				factSet *adaptivecard.Element
+			)
+
+			for i, item := range alertContainer.Items {
+				switch item.Type {
+				case adaptivecard.TypeElementImage:
+					images = append(images, item.URL)
+				case adaptivecard.TypeElementFactSet:
+					factSet = &alertContainer.Items[i]
+				}
+			}
+
+			switch {
+			case tt.expectedImage && !reflect.DeepEqual(images, []string{tt.imageURL}):
+				t.Errorf("got images %v; expected %q", images, tt.imageURL)
+			case !tt.expectedImage && len(images) != 0:
+				t.Errorf("got images %v; expected none", images)
+			}
+
+			expectedFacts := []adaptivecard.Fact{
+				{Title: "A", Value: "0.955"},
+				{Title: "B", Value: "95.5"},
+				{Title: "instance", Value: "web01"},
+			}
+			if factSet == nil || !reflect.DeepEqual(factSet.Facts, expectedFacts) {
+				t.Errorf("got %+v; expected fact set with values and unique labels %v", factSet, expectedFacts)
+			}
+
+			if got := actionTitles(alertContainer.Items); !reflect.DeepEqual(got, tt.expectedActions) {
+				t.Errorf("got actions %v; expected %v", got, tt.expectedActions)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// buildMessage uses the given configuration to generate one or more
+// Microsoft Teams messages ready for delivery. The user-specified payload is
+// used if provided, a notification in the user-specified input format is
+// converted if provided, otherwise a new message is generated from the
+// message text, title and other details. Multiple messages are only
+// generated when an oversized message is split; they should be delivered in
+// order.
+func buildMessage(cfg *config.Config) ([]preparedMessage, error) {
+	switch {
+	case len(cfg.Payload) > 0:
+		prepared, err := newPayloadMessage(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return []preparedMessage{prepared}, nil
+
+	case cfg.InputFormat == config.InputFormatAlertmanager:
+		return newAlertmanagerMessage(cfg)
+
+	case cfg.InputFormat == config.InputFormatGrafana:
+		return newGrafanaMessage(cfg)
+
+	default:
+		return newMessage(cfg)
+	}
+}
+
+// newMessage uses the given configuration to generate a new Microsoft Teams
+// message containing a single Adaptive Card. The card is composed of the
+// message text and title, an optional table and an optional code block
+// along with the common card elements added by newMessageFromCard. The
+// oversize policy is applied by prepareMessages.
+func newMessage(cfg *config.Config) ([]preparedMessage, error) {
+	messageText := cfg.MessageText
+
+	// Convert EOL (useful for output from scripts) in the incoming text if
+	// user requested it.
+	if cfg.ConvertEOL {
+		messageText = adaptivecard.ConvertEOL(messageText)
+
+		// Not 100% safe to apply across the board.
+		//
+		// It is unlikely, but not impossible that someone would submit raw
+		// text with break statements. When you consider that the flag is
+		// named "convert-eol", it is entirely reasonable that the user would
+		// expect break statements to remain untouched.
+		//
+		// messageText = adaptivecard.ConvertBreakToEOL(messageText)
+	}
+
+	// A card with only the (optional) title is used if the message consists
+	// of a table or code block alone.
+	card := adaptivecard.NewCard()
+	switch {
+	case messageText != "":
+		var err error
+		card, err = adaptivecard.NewTextBlockCard(messageText, cfg.MessageTitle, true)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to create new card using specified text/title values: %w",
+				err,
+			)
+		}
+
+	case cfg.MessageTitle != "":
+		card.Body = append(card.Body, adaptivecard.NewTitleTextBlock(cfg.MessageTitle, true))
+	}
+
+	if err := addTable(&card, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := addCodeBlock(&card, cfg.Code, cfg.CodeLanguage); err != nil {
+		return nil, err
+	}
+
+	return prepareMessages(cfg, card, cfg.Severity)
+}
+
+// addCodeBlock adds a code block composed of the given code (e.g., log
+// output or a stack trace) highlighted for the specified language to the
+// given card.
+func addCodeBlock(card *adaptivecard.Card, code string, language string) error {
+	if code == "" {
+		return nil
+	}
+
+	if err := card.AddElement(false, adaptivecard.NewCodeBlock(code, language, 1)); err != nil {
+		return fmt.Errorf("failed to add code block to card: %w", err)
+	}
+
+	return nil
+}
+
+// newMessageFromCard completes the given card and uses it to generate a new
+// Microsoft Teams message. The card is set to full width and optional facts,
+// optional severity styling, optional user mentions, optional target URL
+// "buttons" and (unless disabled) the branding trailer are added.
+func newMessageFromCard(cfg *config.Config, card adaptivecard.Card, severity string) (*adaptivecard.Message, error) {
+	card.SetFullWidth()
+
+	if err := addFacts(&card, cfg.Facts); err != nil {
+		return nil, err
+	}
+
+	if err := applySeverity(&card, severity); err != nil {
+		return nil, err
+	}
+
+	if err := addUserMentions(&card, cfg.UserMentions); err != nil {
+		return nil, err
+	}
+
+	if err := addTargetURLs(&card, cfg.TargetURLs); err != nil {
+		return nil, err
+	}
+
+	// If requested, skip appending the branding trailer to messages.
+	if !cfg.DisableBrandingTrailer {
+		if err := addBrandingTrailer(&card, cfg.Sender); err != nil {
+			return nil, err
+		}
+	}
+
+	message, err := adaptivecard.NewMessageFromCard(card)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to create new message from card: %w",
+			err,
+		)
+	}
+
+	return message, nil
+}
+
+// severityStyle is the styling applied to a card for a specific severity
+// level.
+type severityStyle struct {
+	// containerStyle is the style applied to the container wrapping the
+	// primary content of the card.
+	containerStyle string
+
+	// titleColor is the color applied to the card title.
+	titleColor string
+
+	// titleIcon is the emoji prefixed to the card title.
+	titleIcon string
+}
+
+// severityStyles maps supported severity levels to card styling.
+var severityStyles = map[string]severityStyle{
+	config.SeverityInfo: {
+		containerStyle: adaptivecard.ContainerStyleAccent,
+		titleColor:     adaptivecard.ColorAccent,
+		titleIcon:      "ℹ️",
+	},
+	config.SeverityOK: {
+		containerStyle: adaptivecard.ContainerStyleGood,
+		titleColor:     adaptivecard.ColorGood,
+		titleIcon:      "✅",
+	},
+	config.SeverityWarning: {
+		containerStyle: adaptivecard.ContainerStyleWarning,
+		titleColor:     adaptivecard.ColorWarning,
+		titleIcon:      "⚠️",
+	},
+	config.SeverityCritical: {
+		containerStyle: adaptivecard.ContainerStyleAttention,
+		titleColor:     adaptivecard.ColorAttention,
+		titleIcon:      "🚨",
+	},
+	config.SeverityUnknown: {
+		containerStyle: adaptivecard.ContainerStyleEmphasis,
+		titleColor:     adaptivecard.ColorDefault,
+		titleIcon:      "❔",
+	},
+}
+
+// applySeverity styles the given card based on the specified severity
+// level. The current contents of the card body are moved into a container
+// styled for the severity level and the card title (if present) is colored
+// to match and prefixed with an icon. The card is left as-is if a severity
+// level is not specified.
+func applySeverity(card *adaptivecard.Card, severity string) error {
+	if severity == "" {
+		return nil
+	}
+
+	style, ok := severityStyles[severity]
+	if !ok {
+		return fmt.Errorf("unsupported severity %q", severity)
+	}
+
+	if len(card.Body) == 0 {
+		return nil
+	}
+
+	// NewTextBlockCard inserts the (optional) title as the first element
+	// using the heading style.
+	if card.Body[0].Style == adaptivecard.TextBlockStyleHeading {
+		card.Body[0].Color = style.titleColor
+		card.Body[0].Text = style.titleIcon + " " + card.Body[0].Text
+	}
+
+	severityContainer := adaptivecard.NewContainer()
+	severityContainer.Style = style.containerStyle
+	severityContainer.Items = card.Body
+
+	card.Body = nil
+	if err := card.AddContainer(false, severityContainer); err != nil {
+		return fmt.Errorf("failed to add severity container to card: %w", err)
+	}
+
+	return nil
+}
+
+// addFacts adds a fact set composed of the given facts to the given card.
+func addFacts(card *adaptivecard.Card, facts []config.Fact) error {
+	if len(facts) == 0 {
+		return nil
+	}
+
+	factSet := adaptivecard.NewFactSet()
+	for _, fact := range facts {
+		if err := factSet.AddFact(adaptivecard.Fact{
+			Title: fact.Title,
+			Value: fact.Value,
+		}); err != nil {
+			return fmt.Errorf("failed to process fact %q: %w", fact.Title, err)
+		}
+	}
+
+	if err := card.AddFactSet(false, factSet); err != nil {
+		return fmt.Errorf("failed to add fact set to card: %w", err)
+	}
+
+	return nil
+}
+
+// addUserMentions processes the user mention details specified by the user
+// and attaches the resulting user mentions to the given card.
+func addUserMentions(card *adaptivecard.Card, mentions []config.UserMention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	// Process user mention details specified by user, create user mention
+	// values that we can attach to the card.
+	userMentions := make([]adaptivecard.Mention, 0, len(mentions))
+	for _, mention := range mentions {
+		userMention, err := adaptivecard.NewMention(mention.Name, mention.ID)
+		if err != nil {
+			return fmt.Errorf("failed to process user mention: %w", err)
+		}
+		userMentions = append(userMentions, userMention)
+	}
+
+	// Add user mention collection to card.
+	if err := card.AddMention(true, userMentions...); err != nil {
+		return fmt.Errorf("failed to add user mentions to message: %w", err)
+	}
+
+	return nil
+}
+
+// addTargetURLs uses the given target URLs and their descriptions to add
+// labelled URL "buttons" to the given card.
+func addTargetURLs(card *adaptivecard.Card, targetURLs []config.TargetURL) error {
+	if len(targetURLs) == 0 {
+		return nil
+	}
+
+	// Create dedicated container for all action items.
+	actionsContainer := adaptivecard.NewContainer()
+	actionsContainer.Separator = false
+	actionsContainer.Style = adaptivecard.ContainerStyleEmphasis
+	actionsContainer.Spacing = adaptivecard.SpacingExtraLarge
+
+	actions := make([]adaptivecard.Action, 0, len(targetURLs))
+
+	for i := range targetURLs {
+		urlAction, err := adaptivecard.NewActionOpenURL(
+			targetURLs[i].URL.String(),
+			targetURLs[i].Description,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to process openURL action: %w", err)
+		}
+		actions = append(actions, urlAction)
+	}
+
+	if err := actionsContainer.AddAction(true, actions...); err != nil {
+		return fmt.Errorf("failed to add openURL action to container: %w", err)
+	}
+
+	if err := card.AddContainer(false, actionsContainer); err != nil {
+		return fmt.Errorf("failed to add actions container to card: %w", err)
+	}
+
+	return nil
+}
+
+// addBrandingTrailer appends a container with the branding trailer to the
+// given card, crediting the (optional) sender as the message source.
+func addBrandingTrailer(card *adaptivecard.Card, sender string) error {
+	trailerContainer, err := newBrandingTrailer(sender)
+	if err != nil {
+		return err
+	}
+
+	if err := card.AddContainer(false, trailerContainer); err != nil {
+		return fmt.Errorf("failed to add trailer container to card: %w", err)
+	}
+
+	return nil
+}
+
+// newBrandingTrailer creates a container with the branding trailer,
+// crediting the (optional) sender as the message source.
+func newBrandingTrailer(sender string) (adaptivecard.Container, error) {
+	// Process branding trailer content.
+	//
+	// NOTE: Unlike MessageCard text which has benefited from \r\n
+	// (windows), \r (mac) and \n (unix) conversion to <br> statements in
+	// the past, <br> statements in Adaptive Card text remain as-is in the
+	// final rendered message. This is not useful.
+	trailerText := fmt.Sprintf(
+		"\n\n%s",
+		config.MessageTrailer(sender),
+	)
+
+	trailerContainer := adaptivecard.NewContainer()
+	trailerContainer.Separator = true
+	trailerContainer.Spacing = adaptivecard.SpacingExtraLarge
+
+	trailerTextBlock := adaptivecard.NewTextBlock(trailerText, true)
+	trailerTextBlock.Size = adaptivecard.SizeSmall
+	trailerTextBlock.Weight = adaptivecard.WeightLighter
+
+	if err := trailerContainer.AddElement(false, trailerTextBlock); err != nil {
+		return adaptivecard.Container{}, fmt.Errorf(
+			"failed to add text block to trailer container for card: %w",
+			err,
+		)
+	}
+
+	return trailerContainer, nil
+}
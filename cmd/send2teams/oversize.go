@@ -0,0 +1,378 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// maxMessageSize is the maximum size in bytes of a message payload accepted
+// by Microsoft Teams. The documented limit is approximately 28 KB.
+const maxMessageSize int = 28 * 1024
+
+// truncationMarker is the format of the marker which replaces text removed
+// from a truncated message.
+const truncationMarker string = "\n\n[truncated %d bytes]"
+
+// splitChunkSize is the maximum number of bytes of text placed in a single
+// element when splitting a message. This leaves ample room for JSON encoding
+// overhead and other card elements.
+const splitChunkSize int = maxMessageSize / 4
+
+// ErrMessageTooLarge indicates that a message exceeds the Microsoft Teams
+// message size limit.
+var ErrMessageTooLarge = errors.New("message exceeds Microsoft Teams size limit")
+
+// oversizeError returns an error describing a message of the given size
+// which exceeds the size limit.
+func oversizeError(size int) error {
+	return fmt.Errorf(
+		"%w: payload is %d bytes, limit is %d bytes",
+		ErrMessageTooLarge,
+		size,
+		maxMessageSize,
+	)
+}
+
+// prepareMessages completes the given card, uses it to generate a Microsoft
+// Teams message and prepares the message for delivery. If the prepared
+// payload exceeds the size limit the configured oversize policy is applied;
+// the message is rejected, the card body is truncated or the card body is
+// split across multiple messages which should be delivered in order.
+func prepareMessages(cfg *config.Config, card adaptivecard.Card, severity string) ([]preparedMessage, error) {
+	prepared, err := prepareCard(cfg, card, severity)
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(prepared.payload)
+	if size <= maxMessageSize {
+		return []preparedMessage{prepared}, nil
+	}
+
+	switch cfg.Oversize {
+	case config.OversizeTruncate:
+		prepared, err := truncateCard(cfg, card, severity, size)
+		if err != nil {
+			return nil, err
+		}
+
+		return []preparedMessage{prepared}, nil
+
+	case config.OversizeSplit:
+		return splitCard(cfg, card, severity)
+
+	default:
+		return nil, fmt.Errorf(
+			"%w; use the truncate or split oversize policy to submit it anyway",
+			oversizeError(size),
+		)
+	}
+}
+
+// prepareCard completes a copy of the given card and uses it to generate a
+// prepared message. The given card is left unmodified.
+func prepareCard(cfg *config.Config, card adaptivecard.Card, severity string) (preparedMessage, error) {
+	card.Body = cloneElements(card.Body)
+
+	message, err := newMessageFromCard(cfg, card, severity)
+	if err != nil {
+		return preparedMessage{}, err
+	}
+
+	return newPreparedMessage(message)
+}
+
+// cloneElements returns a copy of the given elements. Nested container
+// items are copied so that their text may be modified independently.
+func cloneElements(elements []adaptivecard.Element) []adaptivecard.Element {
+	if elements == nil {
+		return nil
+	}
+
+	cloned := slices.Clone(elements)
+	for i := range cloned {
+		cloned[i].Items = cloneElements(cloned[i].Items)
+	}
+
+	return cloned
+}
+
+// truncateCard truncates text in the body of the given card until the
+// prepared message fits within the size limit. The longest text is
+// truncated first, leaving a marker noting the number of bytes removed. The
+// card title is retained as are the facts, user mentions, target URL
+// "buttons" and branding trailer added when the card is completed.
+func truncateCard(cfg *config.Config, card adaptivecard.Card, severity string, size int) (preparedMessage, error) {
+	card.Body = cloneElements(card.Body)
+
+	targets := truncationTargets(card.Body)
+	slices.SortStableFunc(targets, func(a *string, b *string) int {
+		return len(*b) - len(*a)
+	})
+
+	for _, target := range targets {
+		original := *target
+		keep := len(original)
+		reserve := len(fmt.Sprintf(truncationMarker, len(original)))
+
+		for keep > 0 {
+			keep -= size - maxMessageSize + reserve
+			reserve = 0
+
+			*target = truncateText(original, max(keep, 0))
+
+			prepared, err := prepareCard(cfg, card, severity)
+			if err != nil {
+				return preparedMessage{}, err
+			}
+
+			size = len(prepared.payload)
+			if size <= maxMessageSize {
+				return prepared, nil
+			}
+		}
+	}
+
+	return preparedMessage{}, fmt.Errorf(
+		"%w; truncating the message body was not sufficient",
+		oversizeError(size),
+	)
+}
+
+// truncationTargets returns pointers to the text of the given elements (and
+// nested container items) which may be truncated. Headings are excluded.
+func truncationTargets(elements []adaptivecard.Element) []*string {
+	var targets []*string
+
+	for i := range elements {
+		element := &elements[i]
+
+		switch element.Type {
+		case adaptivecard.TypeElementTextBlock:
+			if element.Style != adaptivecard.TextBlockStyleHeading && element.Text != "" {
+				targets = append(targets, &element.Text)
+			}
+
+		case adaptivecard.TypeElementMSTeamsCodeBlock:
+			if element.CodeSnippet != "" {
+				targets = append(targets, &element.CodeSnippet)
+			}
+
+		case adaptivecard.TypeElementContainer:
+			targets = append(targets, truncationTargets(element.Items)...)
+		}
+	}
+
+	return targets
+}
+
+// truncateText retains (at most) the first keep bytes of the given text,
+// appending a marker noting the number of bytes removed. A multi-byte
+// character is never split.
+func truncateText(text string, keep int) string {
+	keep = min(keep, len(text))
+	for keep > 0 && keep < len(text) && !utf8.RuneStart(text[keep]) {
+		keep--
+	}
+
+	truncated := text[:keep] + fmt.Sprintf(truncationMarker, len(text)-keep)
+
+	return strings.TrimLeft(truncated, "\n")
+}
+
+// splitCard splits the body of the given card across multiple prepared
+// messages which each fit within the size limit. Each message retains the
+// card title (numbered to indicate the part) and severity styling. Facts,
+// user mentions, target URL "buttons" and the branding trailer are added to
+// the last message only.
+func splitCard(cfg *config.Config, card adaptivecard.Card, severity string) ([]preparedMessage, error) {
+	body := splitElements(card.Body)
+
+	var title *adaptivecard.Element
+	if len(body) > 0 && body[0].Style == adaptivecard.TextBlockStyleHeading {
+		title = &body[0]
+		body = body[1:]
+	}
+
+	// Group elements into parts, checking the size of each part as if it
+	// were the last (and largest) part.
+	var parts [][]adaptivecard.Element
+	var current []adaptivecard.Element
+
+	for _, element := range body {
+		candidate := append(slices.Clone(current), element)
+
+		prepared, err := prepareCard(cfg, partCard(card, title, candidate, 99, 99), severity)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(prepared.payload) <= maxMessageSize {
+			current = candidate
+
+			continue
+		}
+
+		if len(current) == 0 {
+			return nil, fmt.Errorf(
+				"%w; a %s element in the message body is too large to split",
+				oversizeError(len(prepared.payload)),
+				element.Type,
+			)
+		}
+
+		parts = append(parts, current)
+		current = []adaptivecard.Element{element}
+	}
+	parts = append(parts, current)
+
+	messages := make([]preparedMessage, 0, len(parts))
+	for i, part := range parts {
+		partCfg := *cfg
+		if i < len(parts)-1 {
+			partCfg.Facts = nil
+			partCfg.UserMentions = nil
+			partCfg.TargetURLs = nil
+			partCfg.DisableBrandingTrailer = true
+		}
+
+		prepared, err := prepareCard(&partCfg, partCard(card, title, part, i+1, len(parts)), severity)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(prepared.payload) > maxMessageSize {
+			return nil, fmt.Errorf(
+				"%w; failed to split message body into part %d of %d",
+				oversizeError(len(prepared.payload)),
+				i+1,
+				len(parts),
+			)
+		}
+
+		messages = append(messages, prepared)
+	}
+
+	return messages, nil
+}
+
+// partCard returns a copy of the given card whose body is composed of the
+// given title (if any), numbered as the specified part, and elements.
+func partCard(
+	card adaptivecard.Card,
+	title *adaptivecard.Element,
+	elements []adaptivecard.Element,
+	part int,
+	parts int,
+) adaptivecard.Card {
+	body := make([]adaptivecard.Element, 0, len(elements)+1)
+
+	if title != nil {
+		numbered := *title
+		numbered.Text = fmt.Sprintf("%s (%d/%d)", title.Text, part, parts)
+		body = append(body, numbered)
+	}
+
+	card.Body = append(body, elements...)
+
+	return card
+}
+
+// splitElements returns the given elements with the text of large text
+// blocks and code blocks split across multiple elements. Text is split on
+// line boundaries where possible.
+func splitElements(elements []adaptivecard.Element) []adaptivecard.Element {
+	split := make([]adaptivecard.Element, 0, len(elements))
+
+	for _, element := range elements {
+		switch {
+		case element.Type == adaptivecard.TypeElementTextBlock && len(element.Text) > splitChunkSize:
+			for _, chunk := range splitText(element.Text, splitChunkSize) {
+				piece := element
+				piece.Text = chunk.Text
+				split = append(split, piece)
+			}
+
+		case element.Type == adaptivecard.TypeElementMSTeamsCodeBlock && len(element.CodeSnippet) > splitChunkSize:
+			// Continue line numbering from the previous piece. The line
+			// number of each piece is determined from the text preceding it
+			// (including any omitted blank lines). A piece which continues a
+			// line split mid-line starts with that line's number.
+			for _, chunk := range splitText(element.CodeSnippet, splitChunkSize) {
+				piece := element
+				piece.CodeSnippet = chunk.Text
+				piece.StartLineNumber = element.StartLineNumber +
+					strings.Count(element.CodeSnippet[:chunk.Start], "\n")
+				split = append(split, piece)
+			}
+
+		default:
+			split = append(split, element)
+		}
+	}
+
+	return split
+}
+
+// textChunk is a piece of text split from a larger text.
+type textChunk struct {
+	// Text is the text of the chunk without trailing newlines.
+	Text string
+
+	// Start is the offset in bytes of the chunk within the original text.
+	Start int
+
+	// End is the offset in bytes of the end of the text consumed by the
+	// chunk (including trailing newlines) within the original text.
+	End int
+}
+
+// splitText splits the given text into chunks of at most size bytes. Chunks
+// end at a line boundary where possible; long lines are split without
+// splitting multi-byte characters. Trailing newlines are removed from each
+// chunk and chunks consisting only of newlines are omitted; the offsets of
+// each chunk within the given text are retained so that (for example) line
+// numbers may be determined.
+func splitText(text string, size int) []textChunk {
+	var chunks []textChunk
+
+	for start := 0; start < len(text); {
+		end := len(text)
+
+		if end-start > size {
+			end = start + strings.LastIndex(text[start:start+size], "\n") + 1
+			if end == start {
+				end = start + size
+				for end > start && !utf8.RuneStart(text[end]) {
+					end--
+				}
+
+				// Always consume at least one character.
+				if end == start {
+					_, width := utf8.DecodeRuneInString(text[start:])
+					end = start + width
+				}
+			}
+		}
+
+		if chunk := strings.TrimRight(text[start:end], "\n"); chunk != "" {
+			chunks = append(chunks, textChunk{Text: chunk, Start: start, End: end})
+		}
+		start = end
+	}
+
+	return chunks
+}
@@ -0,0 +1,304 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		size     int
+		expected []textChunk
+	}{
+		"shorter than size": {
+			input:    "abc",
+			size:     10,
+			expected: []textChunk{{Text: "abc", Start: 0, End: 3}},
+		},
+		"split at line boundary": {
+			input: "aaa\nbbb\nccc",
+			size:  8,
+			expected: []textChunk{
+				{Text: "aaa\nbbb", Start: 0, End: 8},
+				{Text: "ccc", Start: 8, End: 11},
+			},
+		},
+		"long line split at size": {
+			input: "abcdefghij",
+			size:  4,
+			expected: []textChunk{
+				{Text: "abcd", Start: 0, End: 4},
+				{Text: "efgh", Start: 4, End: 8},
+				{Text: "ij", Start: 8, End: 10},
+			},
+		},
+		"blank lines between chunks are omitted": {
+			input: "aaa\n\n\n\nbbb",
+			size:  4,
+			expected: []textChunk{
+				{Text: "aaa", Start: 0, End: 4},
+				{Text: "bbb", Start: 7, End: 10},
+			},
+		},
+		"trailing newlines": {
+			input:    "abc\n\n",
+			size:     10,
+			expected: []textChunk{{Text: "abc", Start: 0, End: 5}},
+		},
+		"multi-byte characters are not split": {
+			input: "ééé",
+			size:  3,
+			expected: []textChunk{
+				{Text: "é", Start: 0, End: 2},
+				{Text: "é", Start: 2, End: 4},
+				{Text: "é", Start: 4, End: 6},
+			},
+		},
+		"character larger than size": {
+			input: "€€",
+			size:  2,
+			expected: []textChunk{
+				{Text: "€", Start: 0, End: 3},
+				{Text: "€", Start: 3, End: 6},
+			},
+		},
+		"only newlines": {
+			input:    "\n\n\n",
+			size:     2,
+			expected: nil,
+		},
+		"empty": {
+			input:    "",
+			size:     2,
+			expected: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := splitText(tt.input, tt.size)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("got %+v; expected %+v", got, tt.expected)
+			}
+
+			for _, chunk := range got {
+				if len(chunk.Text) > tt.size && tt.size >= 4 {
+					t.Errorf("got %d bytes in chunk %q; expected at most %d", len(chunk.Text), chunk.Text, tt.size)
+				}
+
+				if !strings.HasPrefix(tt.input[chunk.Start:chunk.End], chunk.Text) {
+					t.Errorf("got chunk %q; expected it to match consumed text %q", chunk.Text, tt.input[chunk.Start:chunk.End])
+				}
+			}
+		})
+	}
+}
+
+// numberedLines returns count lines of the given width, each starting with
+// its (1-based) line number.
+func numberedLines(first int, count int, width int) []string {
+	lines := make([]string, 0, count)
+	for i := first; i < first+count; i++ {
+		line := fmt.Sprintf("line %d ", i)
+		lines = append(lines, line+strings.Repeat("x", width-len(line)))
+	}
+
+	return lines
+}
+
+func TestSplitElementsCodeBlockLineNumbers(t *testing.T) {
+	// Lines of 100 bytes (including the newline) so that 71 lines fit
+	// within a single chunk.
+	linesPerChunk := splitChunkSize / 100
+
+	tests := map[string]struct {
+		snippet            string
+		firstLine          int
+		expectedLineNumber []int
+	}{
+		"line boundaries": {
+			snippet:            strings.Join(numberedLines(1, linesPerChunk*2+10, 99), "\n"),
+			firstLine:          1,
+			expectedLineNumber: []int{1, linesPerChunk + 1, linesPerChunk*2 + 1},
+		},
+		"custom first line": {
+			snippet:            strings.Join(numberedLines(10, linesPerChunk+10, 99), "\n"),
+			firstLine:          10,
+			expectedLineNumber: []int{10, linesPerChunk + 10},
+		},
+		"blank lines at chunk boundary are counted": {
+			snippet: strings.Join(numberedLines(1, linesPerChunk, 99), "\n") +
+				"\n\n\n\n" +
+				strings.Join(numberedLines(linesPerChunk+4, 10, 99), "\n"),
+			firstLine:          1,
+			expectedLineNumber: []int{1, linesPerChunk + 4},
+		},
+		"long line split mid-line": {
+			snippet:            "line 1\n" + strings.Repeat("y", splitChunkSize+100) + "\nline 3",
+			firstLine:          1,
+			expectedLineNumber: []int{1, 2, 2},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			element := adaptivecard.NewCodeBlock(tt.snippet, "PlainText", tt.firstLine)
+
+			pieces := splitElements([]adaptivecard.Element{element})
+
+			got := make([]int, 0, len(pieces))
+			for _, piece := range pieces {
+				got = append(got, piece.StartLineNumber)
+
+				if len(piece.CodeSnippet) > splitChunkSize {
+					t.Errorf("got %d bytes; expected at most %d", len(piece.CodeSnippet), splitChunkSize)
+				}
+
+				// Numbered lines must start with their own line number.
+				if strings.HasPrefix(piece.CodeSnippet, "line ") {
+					expectedPrefix := fmt.Sprintf("line %d ", piece.StartLineNumber)
+					firstLine, _, _ := strings.Cut(piece.CodeSnippet, "\n")
+					if !strings.HasPrefix(firstLine+" ", expectedPrefix) {
+						t.Errorf("got first line %q; expected prefix %q", firstLine, expectedPrefix)
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedLineNumber) {
+				t.Errorf("got line numbers %v; expected %v", got, tt.expectedLineNumber)
+			}
+		})
+	}
+}
+
+func TestSplitElementsTextBlock(t *testing.T) {
+	lines := numberedLines(1, splitChunkSize/50*2, 49)
+	text := strings.Join(lines, "\n")
+
+	pieces := splitElements([]adaptivecard.Element{
+		adaptivecard.NewTextBlock("heading", true),
+		adaptivecard.NewTextBlock(text, true),
+	})
+
+	if len(pieces) != 3 {
+		t.Fatalf("got %d elements; expected 3", len(pieces))
+	}
+
+	if pieces[0].Text != "heading" {
+		t.Errorf("got %q; expected small text block to be unmodified", pieces[0].Text)
+	}
+
+	if joined := pieces[1].Text + "\n" + pieces[2].Text; joined != text {
+		t.Errorf("got %d bytes; expected pieces to combine to the original %d bytes", len(joined), len(text))
+	}
+}
+
+func TestPrepareMessagesOversize(t *testing.T) {
+	text := strings.Join(numberedLines(1, maxMessageSize/50, 99), "\n")
+
+	tests := map[string]struct {
+		oversize         string
+		expectedErr      error
+		expectedMessages int
+		expectedText     string
+	}{
+		"fail": {
+			oversize:    config.OversizeFail,
+			expectedErr: ErrMessageTooLarge,
+		},
+		"truncate": {
+			oversize:         config.OversizeTruncate,
+			expectedMessages: 1,
+			expectedText:     "[truncated ",
+		},
+		"split": {
+			oversize:         config.OversizeSplit,
+			expectedMessages: 3,
+			expectedText:     "Oversized (1/3)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Config{
+				Oversize:               tt.oversize,
+				DisableBrandingTrailer: true,
+			}
+
+			card, err := adaptivecard.NewTextBlockCard(text, "Oversized", true)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			messages, err := prepareMessages(cfg, card, config.SeverityWarning)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("got %v; expected error %v", err, tt.expectedErr)
+			}
+
+			if len(messages) != tt.expectedMessages {
+				t.Fatalf("got %d messages; expected %d", len(messages), tt.expectedMessages)
+			}
+
+			for i, message := range messages {
+				if len(message.payload) > maxMessageSize {
+					t.Errorf("got %d bytes for message %d; expected at most %d", len(message.payload), i+1, maxMessageSize)
+				}
+			}
+
+			if tt.expectedMessages > 0 && !strings.Contains(string(messages[0].payload), tt.expectedText) {
+				t.Errorf("got %q; expected first message to contain %q", messages[0].payload, tt.expectedText)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		keep     int
+		expected string
+	}{
+		"retain prefix": {
+			input:    "abcdef",
+			keep:     2,
+			expected: "ab\n\n[truncated 4 bytes]",
+		},
+		"multi-byte character is not split": {
+			input:    "aé",
+			keep:     2,
+			expected: "a\n\n[truncated 2 bytes]",
+		},
+		"nothing retained": {
+			input:    "abc",
+			keep:     0,
+			expected: "[truncated 3 bytes]",
+		},
+		"keep exceeds length": {
+			input:    "abc",
+			keep:     10,
+			expected: "abc\n\n[truncated 0 bytes]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := truncateText(tt.input, tt.keep); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
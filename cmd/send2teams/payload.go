@@ -0,0 +1,225 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// newPayloadMessage uses the user-specified JSON payload to generate a
+// Microsoft Teams message ready for delivery. The payload may be either a
+// complete message or a single Adaptive Card; a single card is wrapped in a
+// new message. The message is validated before use and (unless disabled)
+// the branding trailer is appended to the body of the first card. Payloads
+// exceeding the size limit are rejected.
+//
+// The payload is submitted as provided (aside from the branding trailer) so
+// that properties not modeled by the adaptivecard package are retained.
+func newPayloadMessage(cfg *config.Config) (preparedMessage, error) {
+	payload, err := wrapPayload(cfg.Payload)
+	if err != nil {
+		return preparedMessage{}, err
+	}
+
+	if err := validatePayload(payload); err != nil {
+		return preparedMessage{}, err
+	}
+
+	if !cfg.DisableBrandingTrailer {
+		payload, err = appendBrandingTrailer(payload, cfg.Sender)
+		if err != nil {
+			return preparedMessage{}, err
+		}
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, payload); err != nil {
+		return preparedMessage{}, fmt.Errorf("failed to compact payload: %w", err)
+	}
+
+	// The payload is submitted as provided, so it cannot be truncated or
+	// split regardless of the oversize policy.
+	if compacted.Len() > maxMessageSize {
+		return preparedMessage{}, oversizeError(compacted.Len())
+	}
+
+	return preparedMessage{payload: compacted.Bytes()}, nil
+}
+
+// wrapPayload evaluates the type of the given JSON payload and wraps a single
+// Adaptive Card in a new Microsoft Teams message. A complete message is
+// returned as-is.
+func wrapPayload(payload []byte) ([]byte, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	switch header.Type {
+	case adaptivecard.TypeMessage:
+		return payload, nil
+
+	case adaptivecard.TypeAdaptiveCard:
+		type attachment struct {
+			ContentType string          `json:"contentType"`
+			Content     json.RawMessage `json:"content"`
+		}
+
+		message := struct {
+			Type        string       `json:"type"`
+			Attachments []attachment `json:"attachments"`
+		}{
+			Type: adaptivecard.TypeMessage,
+			Attachments: []attachment{
+				{
+					ContentType: adaptivecard.AttachmentContentType,
+					Content:     payload,
+				},
+			},
+		}
+
+		return json.Marshal(message)
+
+	default:
+		return nil, fmt.Errorf(
+			"unsupported payload type %q; expected %q or %q",
+			header.Type,
+			adaptivecard.TypeMessage,
+			adaptivecard.TypeAdaptiveCard,
+		)
+	}
+}
+
+// validatePayload asserts that the given JSON payload is a valid Microsoft
+// Teams message.
+func validatePayload(payload []byte) error {
+	var message adaptivecard.Message
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return fmt.Errorf("failed to parse payload as message: %w", err)
+	}
+
+	// Checked explicitly as message validation does not reject an empty
+	// collection of attachments.
+	if len(message.Attachments) == 0 {
+		return fmt.Errorf("failed to validate payload: message has no attachments")
+	}
+
+	for i := range message.Attachments {
+		normalizeWidths(message.Attachments[i].Content.Body)
+	}
+
+	if err := message.Validate(); err != nil {
+		return fmt.Errorf("failed to validate payload: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeWidths converts integral column widths decoded from JSON (always
+// float64) to the int values expected by adaptivecard validation. Nested
+// elements are processed recursively.
+func normalizeWidths(elements []adaptivecard.Element) {
+	for i := range elements {
+		normalizeElementWidths(&elements[i])
+	}
+}
+
+// normalizeElementWidths converts integral column widths for the given
+// element and any nested elements.
+func normalizeElementWidths(element *adaptivecard.Element) {
+	normalizeWidths(element.Items)
+
+	for i := range element.Columns {
+		element.Columns[i].Width = normalizeWidth(element.Columns[i].Width)
+
+		for _, item := range element.Columns[i].Items {
+			if item != nil {
+				normalizeElementWidths(item)
+			}
+		}
+	}
+
+	for _, row := range element.Rows {
+		for _, cell := range row.Cells {
+			for _, item := range cell.Items {
+				if item != nil {
+					normalizeElementWidths(item)
+				}
+			}
+		}
+	}
+}
+
+// normalizeWidth converts the given width to an int if it is an integral
+// float64 value, otherwise the width is returned as-is.
+func normalizeWidth(width interface{}) interface{} {
+	if v, ok := width.(float64); ok && v == math.Trunc(v) {
+		return int(v)
+	}
+
+	return width
+}
+
+// appendBrandingTrailer appends the branding trailer to the body of the
+// first card in the given JSON message payload.
+func appendBrandingTrailer(payload []byte, sender string) ([]byte, error) {
+	trailerContainer, err := newBrandingTrailer(sender)
+	if err != nil {
+		return nil, err
+	}
+
+	trailerJSON, err := json.Marshal(trailerContainer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trailer container: %w", err)
+	}
+
+	var trailer interface{}
+	if err := decodeJSON(trailerJSON, &trailer); err != nil {
+		return nil, fmt.Errorf("failed to decode trailer container: %w", err)
+	}
+
+	var message map[string]interface{}
+	if err := decodeJSON(payload, &message); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	// Payload validation asserts that at least one attachment is present.
+	attachments, _ := message["attachments"].([]interface{})
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("failed to add trailer: payload has no attachments")
+	}
+
+	attachment, _ := attachments[0].(map[string]interface{})
+	content, _ := attachment["content"].(map[string]interface{})
+	if content == nil {
+		return nil, fmt.Errorf("failed to add trailer: payload attachment has no content")
+	}
+
+	body, _ := content["body"].([]interface{})
+	content["body"] = append(body, trailer)
+
+	return json.Marshal(message)
+}
+
+// decodeJSON decodes the given JSON data into v retaining numbers as-is
+// rather than converting them to float64 values.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
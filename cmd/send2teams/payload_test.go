@@ -0,0 +1,222 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+const testCardPayload string = `{
+	"type": "AdaptiveCard",
+	"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+	"version": "1.5",
+	"body": [
+		{"type": "TextBlock", "text": "hello", "wrap": true},
+		{
+			"type": "ColumnSet",
+			"columns": [
+				{"type": "Column", "width": 1, "items": [{"type": "TextBlock", "text": "left"}]},
+				{"type": "Column", "width": "stretch", "items": [{"type": "TextBlock", "text": "right"}]}
+			]
+		}
+	],
+	"x-custom": {"retained": true}
+}`
+
+const testMessagePayload string = `{
+	"type": "message",
+	"attachments": [
+		{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": ` + testCardPayload + `
+		}
+	]
+}`
+
+func TestWrapPayload(t *testing.T) {
+	tests := map[string]struct {
+		payload   string
+		expectErr bool
+	}{
+		"card": {
+			payload: testCardPayload,
+		},
+		"message": {
+			payload: testMessagePayload,
+		},
+		"unsupported type": {
+			payload:   `{"type": "MessageCard"}`,
+			expectErr: true,
+		},
+		"missing type": {
+			payload:   `{"body": []}`,
+			expectErr: true,
+		},
+		"invalid JSON": {
+			payload:   `{"type": `,
+			expectErr: true,
+		},
+		"not an object": {
+			payload:   `["AdaptiveCard"]`,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := wrapPayload([]byte(tt.payload))
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got %s; expected error", got)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectErr:
+				return
+			}
+
+			var message struct {
+				Type        string `json:"type"`
+				Attachments []struct {
+					ContentType string                 `json:"contentType"`
+					Content     map[string]interface{} `json:"content"`
+				} `json:"attachments"`
+			}
+			if err := json.Unmarshal(got, &message); err != nil {
+				t.Fatalf("got %v; expected wrapped payload to be valid JSON", err)
+			}
+
+			switch {
+			case message.Type != adaptivecard.TypeMessage:
+				t.Errorf("got type %q; expected %q", message.Type, adaptivecard.TypeMessage)
+			case len(message.Attachments) != 1:
+				t.Errorf("got %d attachments; expected 1", len(message.Attachments))
+			case message.Attachments[0].ContentType != adaptivecard.AttachmentContentType:
+				t.Errorf("got content type %q; expected %q", message.Attachments[0].ContentType, adaptivecard.AttachmentContentType)
+			case message.Attachments[0].Content["x-custom"] == nil:
+				t.Errorf("got %s; expected unmodeled card properties to be retained", got)
+			}
+		})
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	tests := map[string]struct {
+		payload   string
+		expectErr bool
+	}{
+		"valid message": {
+			payload: testMessagePayload,
+		},
+		"no attachments": {
+			payload:   `{"type": "message", "attachments": []}`,
+			expectErr: true,
+		},
+		"invalid element type": {
+			payload: `{"type": "message", "attachments": [{"contentType": "application/vnd.microsoft.card.adaptive", "content": {
+				"type": "AdaptiveCard", "version": "1.5", "body": [{"type": "Unknown"}]}}]}`,
+			expectErr: true,
+		},
+		"invalid JSON": {
+			payload:   `{"type": "message", "attachments": `,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validatePayload([]byte(tt.payload))
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Errorf("got nil; expected error")
+			case !tt.expectErr && err != nil:
+				t.Errorf("got %v; expected no error", err)
+			}
+		})
+	}
+}
+
+func TestNewPayloadMessage(t *testing.T) {
+	tests := map[string]struct {
+		payload        string
+		disableTrailer bool
+		expectedErr    error
+		expectTrailer  bool
+		expectErr      bool
+	}{
+		"card with trailer": {
+			payload:       testCardPayload,
+			expectTrailer: true,
+		},
+		"message without trailer": {
+			payload:        testMessagePayload,
+			disableTrailer: true,
+		},
+		"oversized": {
+			payload:     strings.Replace(testCardPayload, `"hello"`, `"`+strings.Repeat("x", maxMessageSize)+`"`, 1),
+			expectErr:   true,
+			expectedErr: ErrMessageTooLarge,
+		},
+		"invalid": {
+			payload:   `{"type": "MessageCard"}`,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Config{
+				Payload:                []byte(tt.payload),
+				DisableBrandingTrailer: tt.disableTrailer,
+			}
+
+			prepared, err := newPayloadMessage(cfg)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got nil; expected error")
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case tt.expectErr:
+				return
+			}
+
+			if err := validatePayload(prepared.payload); err != nil {
+				t.Fatalf("got %v; expected prepared payload to be valid", err)
+			}
+
+			if !strings.Contains(string(prepared.payload), `"x-custom"`) {
+				t.Errorf("got %s; expected unmodeled card properties to be retained", prepared.payload)
+			}
+
+			var message adaptivecard.Message
+			if err := json.Unmarshal(prepared.payload, &message); err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			body := message.Attachments[0].Content.Body
+			expectedElements := 2
+			if tt.expectTrailer {
+				expectedElements++
+			}
+
+			if len(body) != expectedElements {
+				t.Errorf("got %d body elements; expected %d", len(body), expectedElements)
+			}
+		})
+	}
+}
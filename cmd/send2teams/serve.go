@@ -0,0 +1,349 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// alertEndpoint is the path of the endpoint which accepts alerts submitted
+// by clients.
+const alertEndpoint string = "/alert"
+
+// alertmanagerEndpoint is the path of the endpoint which accepts Prometheus
+// Alertmanager webhook notifications.
+const alertmanagerEndpoint string = "/alertmanager"
+
+// grafanaEndpoint is the path of the endpoint which accepts Grafana alerting
+// webhook notifications.
+const grafanaEndpoint string = "/grafana"
+
+// destinationParam is the name of the query parameter used to specify the
+// destination for notifications submitted to input format endpoints.
+const destinationParam string = "destination"
+
+// maxRequestSize is the maximum size in bytes of a request body accepted by
+// the server.
+const maxRequestSize int64 = 1 << 20
+
+// readHeaderTimeout is the amount of time allowed to read request headers.
+const readHeaderTimeout time.Duration = 10 * time.Second
+
+// Statuses reported to clients submitting an alert.
+const (
+	// alertStatusSent indicates that the alert was delivered.
+	alertStatusSent string = "sent"
+
+	// alertStatusSpooled indicates that delivery of the alert failed for at
+	// least one webhook URL and the alert was written to the spool
+	// directory for later delivery.
+	alertStatusSpooled string = "spooled"
+
+	// alertStatusFailed indicates that the alert was not delivered.
+	alertStatusFailed string = "failed"
+)
+
+// alertRequest is an alert submitted by a client for delivery to Microsoft
+// Teams.
+type alertRequest struct {
+	// Title is the (optional) title for the message.
+	Title string `json:"title"`
+
+	// Message is the (optionally) Markdown-formatted message text.
+	Message string `json:"message"`
+
+	// Severity is the (optional) severity level used to style the message.
+	Severity string `json:"severity"`
+
+	// Facts is the (optional) collection of facts displayed below the
+	// message text.
+	Facts []alertFact `json:"facts"`
+
+	// Destination is the (optional) name of a destination defined in the
+	// configuration file. If not specified, the default settings are used.
+	Destination string `json:"destination"`
+}
+
+// alertFact is a fact submitted as part of an alert.
+type alertFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// alertResponse is the response returned to clients submitting an alert.
+type alertResponse struct {
+	// Status is the overall result of processing the alert.
+	Status string `json:"status"`
+
+	// Error is a description of the problem encountered (if any). Details
+	// which could reveal webhook URLs are omitted.
+	Error string `json:"error,omitempty"`
+}
+
+// relay relays alerts submitted by clients to Microsoft Teams.
+type relay struct {
+	cfg       *config.Config
+	mstClient *goteamsnotify.TeamsClient
+}
+
+// serve runs an HTTP server relaying alerts submitted by clients to
+// Microsoft Teams until an interrupt or termination signal is received. In
+// flight alerts are given time to complete before the server stops.
+func serve(cfg *config.Config) error {
+	mstClient := newTeamsClient(cfg)
+
+	// Webhook URLs for the default settings and each destination were
+	// validated (unless disabled for that destination) at startup.
+	mstClient.SkipWebhookURLValidationOnSend(true)
+
+	mux := http.NewServeMux()
+	rl := relay{cfg: cfg, mstClient: mstClient}
+
+	mux.HandleFunc(alertEndpoint, rl.handleAlert)
+	mux.HandleFunc(alertmanagerEndpoint, rl.handleInput(config.InputFormatAlertmanager))
+	mux.HandleFunc(grafanaEndpoint, rl.handleInput(config.InputFormatGrafana))
+
+	server := &http.Server{
+		Addr:              cfg.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	if !cfg.SilentOutput {
+		log.Printf(
+			"Listening for alerts on %s (destinations: %s)",
+			cfg.ListenAddress,
+			strings.Join(cfg.DestinationNames(), ", "),
+		)
+	}
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server stopped unexpectedly: %w", err)
+	case <-ctx.Done():
+	}
+
+	if !cfg.SilentOutput {
+		log.Println("Shutting down; waiting for in flight alerts to complete")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.TeamsSubmissionTimeout())
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown server: %w", err)
+	}
+
+	return nil
+}
+
+// handleAlert validates the alert submitted by a client and relays it to
+// the webhook URLs for the requested destination.
+func (rl relay) handleAlert(w http.ResponseWriter, r *http.Request) {
+	if !rl.allowPost(w, r) {
+		return
+	}
+
+	var alert alertRequest
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&alert); err != nil {
+		rl.respond(w, http.StatusBadRequest, fmt.Sprintf("invalid alert: %v", err))
+
+		return
+	}
+
+	msgCfg, err := rl.messageConfig(alert)
+	if err != nil {
+		rl.respondConfigError(w, err)
+
+		return
+	}
+
+	rl.submit(w, r, msgCfg)
+}
+
+// handleInput returns a handler which relays notifications in the specified
+// input format (e.g., Alertmanager webhook notifications) to the webhook
+// URLs for the destination specified via query parameter.
+func (rl relay) handleInput(inputFormat string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !rl.allowPost(w, r) {
+			return
+		}
+
+		input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+		if err != nil {
+			rl.respond(w, http.StatusBadRequest, fmt.Sprintf("invalid %s notification: %v", inputFormat, err))
+
+			return
+		}
+
+		msgCfg, err := rl.cfg.MessageConfig(r.URL.Query().Get(destinationParam))
+		if err != nil {
+			rl.respondConfigError(w, err)
+
+			return
+		}
+
+		msgCfg.InputFormat = inputFormat
+		msgCfg.Input = input
+
+		if err := msgCfg.Validate(msgCfg.DisableWebhookURLValidation); err != nil {
+			rl.respondConfigError(w, err)
+
+			return
+		}
+
+		rl.submit(w, r, msgCfg)
+	}
+}
+
+// allowPost asserts that the request uses the POST method, responding with
+// an error if it does not.
+func (rl relay) allowPost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		rl.respond(w, http.StatusMethodNotAllowed, "method not allowed")
+
+		return false
+	}
+
+	return true
+}
+
+// submit generates a message using the given configuration and delivers it
+// to the configured webhook URLs, responding to the client with the result.
+func (rl relay) submit(w http.ResponseWriter, r *http.Request, msgCfg config.Config) {
+	messages, err := buildMessage(&msgCfg)
+	if err != nil {
+		rl.logf("ERROR: Failed to create message for %s: %v", r.RemoteAddr, err)
+		rl.respond(w, http.StatusBadRequest, fmt.Sprintf("failed to create message: %v", err))
+
+		return
+	}
+
+	spooled, err := deliverMessages(r.Context(), rl.mstClient, &msgCfg, messages)
+	switch {
+	case err != nil:
+		rl.respond(w, http.StatusBadGateway, err.Error())
+
+	// Delivery to at least one webhook URL failed, but the message was
+	// written to the spool directory for later delivery.
+	case spooled:
+		rl.respondStatus(w, http.StatusAccepted, alertStatusSpooled)
+
+	default:
+		rl.respond(w, http.StatusOK, "")
+	}
+}
+
+// respondConfigError responds to the client with an error encountered when
+// preparing the configuration for a message.
+func (rl relay) respondConfigError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, config.ErrDestinationNotFound):
+		rl.respond(w, http.StatusNotFound, err.Error())
+	default:
+		rl.respond(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// messageConfig returns the configuration for submitting the given alert to
+// the requested destination.
+func (rl relay) messageConfig(alert alertRequest) (config.Config, error) {
+	msgCfg, err := rl.cfg.MessageConfig(alert.Destination)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	if strings.TrimSpace(alert.Message) == "" {
+		return config.Config{}, fmt.Errorf("message content too short")
+	}
+
+	msgCfg.MessageText = alert.Message
+	if alert.Title != "" {
+		msgCfg.MessageTitle = alert.Title
+	}
+
+	if alert.Severity != "" {
+		msgCfg.Severity = strings.ToLower(strings.TrimSpace(alert.Severity))
+	}
+
+	for _, fact := range alert.Facts {
+		if fact.Title == "" || fact.Value == "" {
+			return config.Config{}, fmt.Errorf("fact title and value must not be empty")
+		}
+		msgCfg.Facts = append(msgCfg.Facts, config.Fact{Title: fact.Title, Value: fact.Value})
+	}
+
+	if err := msgCfg.Validate(msgCfg.DisableWebhookURLValidation); err != nil {
+		return config.Config{}, err
+	}
+
+	return msgCfg, nil
+}
+
+// respond writes a JSON response with the given status code and error
+// message (if any) to the client.
+func (rl relay) respond(w http.ResponseWriter, statusCode int, errMsg string) {
+	resp := alertResponse{Status: alertStatusSent}
+	if errMsg != "" {
+		resp.Status = alertStatusFailed
+		resp.Error = rl.cfg.Redact(errMsg)
+	}
+
+	rl.writeResponse(w, statusCode, resp)
+}
+
+// respondStatus writes a JSON response with the given status code and
+// status to the client.
+func (rl relay) respondStatus(w http.ResponseWriter, statusCode int, status string) {
+	rl.writeResponse(w, statusCode, alertResponse{Status: status})
+}
+
+// writeResponse writes the given JSON response with the given status code
+// to the client.
+func (rl relay) writeResponse(w http.ResponseWriter, statusCode int, resp alertResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		rl.logf("ERROR: Failed to write response: %v", err)
+	}
+}
+
+// logf emits the given log message unless silent output was requested.
+func (rl relay) logf(format string, v ...interface{}) {
+	if !rl.cfg.SilentOutput {
+		log.Print(rl.cfg.Redact(fmt.Sprintf(format, v...)))
+	}
+}
@@ -0,0 +1,520 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// spoolEntryVersion is the version of the spool entry format written by this
+// application.
+const spoolEntryVersion int = 2
+
+// Names used within the spool directory.
+const (
+	// spoolEntrySuffix is the file name suffix of spool entries.
+	spoolEntrySuffix string = ".json"
+
+	// spoolLockFile is the name of the lock file locked by the flush command
+	// while spooled messages are processed.
+	spoolLockFile string = ".lock"
+
+	// spoolDeadLetterDir is the name of the subdirectory to which spooled
+	// messages are moved if they cannot be delivered.
+	spoolDeadLetterDir string = "dead"
+)
+
+// Permissions used for the spool directory and spool entries. Spooled
+// messages are only accessible by the current user.
+const (
+	spoolDirPerm  os.FileMode = 0o700
+	spoolFilePerm os.FileMode = 0o600
+)
+
+// ErrSpoolLocked indicates that the spool directory is locked by another
+// invocation of the flush command.
+var ErrSpoolLocked = errors.New("spool directory is locked by another flush")
+
+// ErrInsecureSpoolDir indicates that the spool directory is accessible by
+// group or other users.
+var ErrInsecureSpoolDir = errors.New("spool directory is accessible by other users")
+
+// spoolEntry is a prepared message written to the spool directory after
+// delivery to a webhook URL failed, along with the details needed to
+// deliver it later.
+type spoolEntry struct {
+	// Version is the version of the spool entry format.
+	Version int `json:"version"`
+
+	// Destination is the webhook URL the message is delivered to as
+	// configured (e.g., encrypted or base64 encoded). The webhook URL is
+	// decrypted or decoded when the message is delivered so that it is not
+	// written to the spool directory in a more readable form than it was
+	// provided.
+	Destination string `json:"destination"`
+
+	// Team is the name of the team containing the target channel. Used in
+	// log messages.
+	Team string `json:"team,omitempty"`
+
+	// Channel is the name of the target channel. Used in log messages.
+	Channel string `json:"channel,omitempty"`
+
+	// DisableWebhookURLValidation indicates whether webhook URL validation
+	// was disabled when the message was spooled.
+	DisableWebhookURLValidation bool `json:"disable-url-validation,omitempty"`
+
+	// IgnoreInvalidResponse indicates whether an invalid response from the
+	// remote endpoint was ignored when the message was spooled.
+	IgnoreInvalidResponse bool `json:"ignore-invalid-response,omitempty"`
+
+	// FirstFailure is the time that the message was spooled.
+	FirstFailure time.Time `json:"first-failure"`
+
+	// LastAttempt is the time of the most recent delivery attempt.
+	LastAttempt time.Time `json:"last-attempt"`
+
+	// Attempts is the number of delivery attempts (each of which may
+	// include retries) made for the message.
+	Attempts int `json:"attempts"`
+
+	// LastError is the error encountered by the most recent delivery
+	// attempt.
+	LastError string `json:"last-error,omitempty"`
+
+	// Payload is the prepared JSON payload of the message.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// spoolEntryName returns a new unique spool entry file name. Names sort in
+// the order entries were created.
+func spoolEntryName() (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate spool entry name: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"%020d-%s%s",
+		time.Now().UnixNano(),
+		hex.EncodeToString(suffix),
+		spoolEntrySuffix,
+	), nil
+}
+
+// ensureSpoolDir creates the given spool (or dead-letter) directory if
+// needed, accessible only by the current user. An error is returned if an
+// existing directory is accessible by group or other users. Directory
+// permissions are not checked on Windows.
+func ensureSpoolDir(dir string) error {
+	if err := os.MkdirAll(dir, spoolDirPerm); err != nil {
+		return fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to access spool directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("spool directory %q is not a directory", dir)
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&^spoolDirPerm != 0 {
+		return fmt.Errorf(
+			"%w: %q has permissions %s; restrict access (e.g., chmod 700)",
+			ErrInsecureSpoolDir,
+			dir,
+			info.Mode().Perm(),
+		)
+	}
+
+	return nil
+}
+
+// writeSpoolEntry writes the given entry to the specified file in the given
+// directory. The directory is created (accessible only by the current user)
+// if needed and the file (readable only by the current user) is replaced
+// atomically so that a partial entry is never observed.
+func writeSpoolEntry(dir string, name string, entry spoolEntry) error {
+	if err := ensureSpoolDir(dir); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode spool entry: %w", err)
+	}
+
+	// Temporary files are ignored by the flush command.
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary spool entry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := tmp.Chmod(spoolFilePerm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set spool entry permissions: %w", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write spool entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write spool entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to replace spool entry: %w", err)
+	}
+
+	return nil
+}
+
+// readSpoolEntry reads the spool entry from the specified file.
+func readSpoolEntry(path string) (spoolEntry, error) {
+	// #nosec G304 -- file path is within the spool directory provided by
+	// the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spoolEntry{}, fmt.Errorf("failed to read spool entry: %w", err)
+	}
+
+	var entry spoolEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return spoolEntry{}, fmt.Errorf("failed to parse spool entry: %w", err)
+	}
+
+	switch {
+	case entry.Version != spoolEntryVersion:
+		return spoolEntry{}, fmt.Errorf("unsupported spool entry version %d", entry.Version)
+	case entry.Destination == "":
+		return spoolEntry{}, fmt.Errorf("spool entry destination not specified")
+	case len(entry.Payload) == 0:
+		return spoolEntry{}, fmt.Errorf("spool entry payload not specified")
+	}
+
+	return entry, nil
+}
+
+// spoolMessage writes the given prepared message for the specified
+// destination (the webhook URL as configured) to the spool directory. The
+// decoded webhook URL is only used for log messages. The error encountered
+// when delivering the message is recorded; a nil error indicates that
+// delivery was not attempted.
+func spoolMessage(
+	cfg *config.Config,
+	destination string,
+	webhookURL string,
+	prepared preparedMessage,
+	deliveryErr error,
+) error {
+	name, err := spoolEntryName()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	entry := spoolEntry{
+		Version:                     spoolEntryVersion,
+		Destination:                 destination,
+		Team:                        cfg.Team,
+		Channel:                     cfg.Channel,
+		DisableWebhookURLValidation: cfg.DisableWebhookURLValidation,
+		IgnoreInvalidResponse:       cfg.IgnoreInvalidResponse,
+		FirstFailure:                now,
+		Payload:                     prepared.payload,
+	}
+
+	if deliveryErr != nil {
+		entry.LastAttempt = now
+		entry.Attempts = 1
+		entry.LastError = cfg.Redact(deliveryErr.Error())
+	}
+
+	if err := writeSpoolEntry(cfg.SpoolDir, name, entry); err != nil {
+		return err
+	}
+
+	if !cfg.SilentOutput {
+		log.Printf("Message for %s written to spool directory as %s", spoolTargetLabel(webhookURL), name)
+	}
+
+	return nil
+}
+
+// spoolFailedDeliveries writes the given prepared message to the spool
+// directory for each webhook URL it could not be delivered to.
+func spoolFailedDeliveries(cfg *config.Config, results deliveryResults, prepared preparedMessage) error {
+	for _, result := range results {
+		if result.Err == nil || result.Ignored {
+			continue
+		}
+
+		if err := spoolMessage(cfg, result.Destination, result.URL, prepared, result.Err); err != nil {
+			return fmt.Errorf("failed to spool message for %s: %w", result.Target, err)
+		}
+	}
+
+	return nil
+}
+
+// spoolTargetLabel returns a human-readable label for the given webhook URL
+// suitable for use in log messages.
+func spoolTargetLabel(webhookURL string) string {
+	if u, err := url.Parse(webhookURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+
+	return "webhook URL"
+}
+
+// spoolEntryNames returns the names of the spool entries in the given
+// directory in the order they were created.
+func spoolEntryNames(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	// ReadDir returns entries sorted by file name.
+	names := make([]string, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if !dirEntry.Type().IsRegular() ||
+			strings.HasPrefix(name, ".") ||
+			!strings.HasSuffix(name, spoolEntrySuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// flushSummary is the outcome of delivering the messages in the spool
+// directory.
+type flushSummary struct {
+	// Delivered is the number of messages delivered.
+	Delivered int
+
+	// Remaining is the number of messages left in the spool directory.
+	Remaining int
+
+	// DeadLettered is the number of messages moved to the dead-letter
+	// directory.
+	DeadLettered int
+}
+
+// runFlush delivers the messages in the spool directory and returns the
+// application exit code. A non-zero exit code is returned if any message
+// could not be delivered.
+func runFlush(cfg *config.Config) int {
+	summary, err := flushSpool(cfg)
+	if err != nil {
+		if !cfg.SilentOutput {
+			log.Printf("\n\nERROR: Failed to flush spool directory: %v\n\n", cfg.RedactError(err))
+		}
+
+		return 1
+	}
+
+	if !cfg.SilentOutput {
+		log.Printf(
+			"Spooled messages delivered: %d, remaining: %d, moved to dead-letter directory: %d",
+			summary.Delivered,
+			summary.Remaining,
+			summary.DeadLettered,
+		)
+	}
+
+	if summary.Remaining > 0 || summary.DeadLettered > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// flushSpool delivers the messages in the spool directory in the order they
+// were spooled. If delivery to a webhook URL fails, later messages for the
+// same webhook URL are left in the spool directory so that they are
+// delivered in order by a later flush. Messages which have expired, have
+// reached the maximum number of delivery attempts or cannot be read are
+// moved to the dead-letter directory. The spool directory is locked while
+// messages are processed.
+func flushSpool(cfg *config.Config) (flushSummary, error) {
+	var summary flushSummary
+
+	if err := ensureSpoolDir(cfg.SpoolDir); err != nil {
+		return summary, err
+	}
+
+	unlock, err := lockSpool(cfg.SpoolDir)
+	if err != nil {
+		return summary, err
+	}
+	defer unlock()
+
+	names, err := spoolEntryNames(cfg.SpoolDir)
+	if err != nil {
+		return summary, err
+	}
+
+	// Destinations for which delivery failed during this flush.
+	blocked := make(map[string]bool)
+
+	for _, name := range names {
+		path := filepath.Join(cfg.SpoolDir, name)
+
+		entry, err := readSpoolEntry(path)
+		if err != nil {
+			if moveErr := deadLetterSpoolFile(cfg, name); moveErr != nil {
+				return summary, moveErr
+			}
+			summary.DeadLettered++
+			flushLogf(cfg, "Spooled message %s moved to dead-letter directory: %v", name, err)
+
+			continue
+		}
+
+		switch {
+		case blocked[entry.Destination]:
+			summary.Remaining++
+
+			continue
+
+		case time.Since(entry.FirstFailure) > cfg.SpoolMaxAge:
+			if err := deadLetterSpoolFile(cfg, name); err != nil {
+				return summary, err
+			}
+			summary.DeadLettered++
+			flushLogf(cfg, "Spooled message %s moved to dead-letter directory: expired after %v", name, cfg.SpoolMaxAge)
+
+			continue
+		}
+
+		// Encrypted webhook URLs are decrypted using the key specified for
+		// the flush command. A missing or incorrect key is treated as a
+		// delivery failure so that the message is retained.
+		webhookURL, deliveryErr := cfg.DecodeWebhookURL(entry.Destination)
+		if deliveryErr == nil {
+			deliveryErr = deliverSpoolEntry(cfg, webhookURL, entry)
+		}
+
+		if deliveryErr == nil {
+			if err := os.Remove(path); err != nil {
+				return summary, fmt.Errorf("failed to remove delivered spool entry: %w", err)
+			}
+			summary.Delivered++
+			flushLogf(cfg, "Spooled message %s successfully sent to %s", name, spoolTargetLabel(webhookURL))
+
+			continue
+		}
+
+		blocked[entry.Destination] = true
+
+		entry.Attempts++
+		entry.LastAttempt = time.Now().UTC()
+		entry.LastError = cfg.Redact(deliveryErr.Error())
+
+		if err := writeSpoolEntry(cfg.SpoolDir, name, entry); err != nil {
+			return summary, err
+		}
+
+		if entry.Attempts >= cfg.SpoolMaxAttempts {
+			if err := deadLetterSpoolFile(cfg, name); err != nil {
+				return summary, err
+			}
+			summary.DeadLettered++
+			flushLogf(
+				cfg,
+				"Spooled message %s moved to dead-letter directory after %d attempts: %v",
+				name,
+				entry.Attempts,
+				deliveryErr,
+			)
+
+			continue
+		}
+
+		summary.Remaining++
+		flushLogf(cfg, "Failed to deliver spooled message %s to %s: %v", name, spoolTargetLabel(webhookURL), deliveryErr)
+	}
+
+	return summary, nil
+}
+
+// deliverSpoolEntry submits the message recorded in the given spool entry to
+// the given (decoded) webhook URL within the configured timeout, retrying
+// submission as needed up to the configured number of retry attempts.
+func deliverSpoolEntry(cfg *config.Config, webhookURL string, entry spoolEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TeamsSubmissionTimeout())
+	defer cancel()
+
+	mstClient := newTeamsClient(cfg)
+	if entry.DisableWebhookURLValidation {
+		mstClient.SkipWebhookURLValidationOnSend(true)
+	}
+
+	err := mstClient.SendWithRetry(
+		ctx,
+		webhookURL,
+		preparedMessage{payload: entry.Payload},
+		cfg.Retries,
+		cfg.RetriesDelay,
+	)
+
+	ignore := cfg.IgnoreInvalidResponse || entry.IgnoreInvalidResponse
+	if ignore && errors.Is(err, goteamsnotify.ErrInvalidWebhookURLResponseText) {
+		return nil
+	}
+
+	return err
+}
+
+// deadLetterSpoolFile moves the specified spool entry to the dead-letter
+// directory.
+func deadLetterSpoolFile(cfg *config.Config, name string) error {
+	deadLetterDir := filepath.Join(cfg.SpoolDir, spoolDeadLetterDir)
+
+	if err := ensureSpoolDir(deadLetterDir); err != nil {
+		return fmt.Errorf("failed to prepare dead-letter directory: %w", err)
+	}
+
+	if err := os.Rename(filepath.Join(cfg.SpoolDir, name), filepath.Join(deadLetterDir, name)); err != nil {
+		return fmt.Errorf("failed to move spool entry to dead-letter directory: %w", err)
+	}
+
+	return nil
+}
+
+// flushLogf emits a log message describing the processing of a spooled
+// message unless silent output was requested.
+func flushLogf(cfg *config.Config, format string, v ...interface{}) {
+	if !cfg.SilentOutput {
+		log.Print(cfg.Redact(fmt.Sprintf(format, v...)))
+	}
+}
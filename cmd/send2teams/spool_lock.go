@@ -0,0 +1,49 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockSpool acquires an exclusive lock on the lock file for the given spool
+// directory, returning a function which releases it. The lock is held for
+// as long as the lock file remains open and is released by the operating
+// system if the flush command does not exit cleanly. The lock file is left
+// in place so that every invocation locks the same file.
+func lockSpool(dir string) (func(), error) {
+	lockPath := filepath.Join(dir, spoolLockFile)
+
+	// #nosec G304 -- file path is within the spool directory provided by
+	// the user
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w (lock file %s)", ErrSpoolLocked, lockPath)
+		}
+
+		return nil, fmt.Errorf("failed to lock spool lock file: %w", err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+)
+
+// errorSharingViolation is the Windows ERROR_SHARING_VIOLATION error code
+// returned when a file is opened by another process without sharing.
+const errorSharingViolation syscall.Errno = 32
+
+// lockSpool acquires an exclusive lock on the lock file for the given spool
+// directory, returning a function which releases it. The lock file is
+// opened without sharing so that other invocations are unable to open it
+// while the lock is held. The lock is released by the operating system if
+// the flush command does not exit cleanly. The lock file is left in place so
+// that every invocation locks the same file.
+func lockSpool(dir string) (func(), error) {
+	lockPath := filepath.Join(dir, spoolLockFile)
+
+	name, err := syscall.UTF16PtrFromString(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool lock file: %w", err)
+	}
+
+	h, err := syscall.CreateFile(
+		name,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0, // no sharing
+		nil,
+		syscall.OPEN_ALWAYS,
+		syscall.FILE_ATTRIBUTE_NORMAL,
+		0,
+	)
+	if err != nil {
+		if errors.Is(err, errorSharingViolation) {
+			return nil, fmt.Errorf("%w (lock file %s)", ErrSpoolLocked, lockPath)
+		}
+
+		return nil, fmt.Errorf("failed to open spool lock file: %w", err)
+	}
+
+	return func() { _ = syscall.CloseHandle(h) }, nil
+}
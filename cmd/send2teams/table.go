@@ -0,0 +1,148 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// tableSizeLimit is the maximum size in bytes of the JSON encoded rows of a
+// table generated from tabular data. Rows beyond the limit are omitted so
+// that the remainder of the message fits within the message size limit.
+const tableSizeLimit int = maxMessageSize / 2
+
+// addTable adds a table generated from the tabular data provided by the user
+// to the given card. Columns and rows beyond the configured limits (or the
+// table size limit) are omitted and noted below the table.
+func addTable(card *adaptivecard.Card, cfg *config.Config) error {
+	if len(cfg.Table) == 0 {
+		return nil
+	}
+
+	table, note, err := newDataTable(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := card.AddElement(false, table); err != nil {
+		return fmt.Errorf("failed to add table to card: %w", err)
+	}
+
+	if note == "" {
+		return nil
+	}
+
+	noteTextBlock := adaptivecard.NewTextBlock(note, true)
+	noteTextBlock.Size = adaptivecard.SizeSmall
+	noteTextBlock.IsSubtle = true
+
+	if err := card.AddElement(false, noteTextBlock); err != nil {
+		return fmt.Errorf("failed to add table note to card: %w", err)
+	}
+
+	return nil
+}
+
+// newDataTable creates a table from the tabular data provided by the user
+// along with a note describing any omitted columns or rows. Rows with fewer
+// fields than the widest row are padded with empty cells.
+func newDataTable(cfg *config.Config) (adaptivecard.Element, string, error) {
+	var totalColumns int
+	for _, row := range cfg.Table {
+		totalColumns = max(totalColumns, len(row))
+	}
+	columns := min(totalColumns, cfg.TableMaxColumns)
+
+	dataRows := len(cfg.Table)
+	if cfg.TableHeader {
+		dataRows--
+	}
+
+	maxRows := cfg.TableMaxRows
+	if cfg.TableHeader {
+		maxRows++
+	}
+
+	rows := make([][]adaptivecard.TableCell, 0, min(len(cfg.Table), maxRows))
+	var size int
+
+	for i, row := range cfg.Table {
+		if i >= maxRows {
+			break
+		}
+
+		// Empty cells are used for missing or empty fields.
+		items := make([]interface{}, columns)
+		for j := range items {
+			if j < len(row) && row[j] != "" {
+				items[j] = row[j]
+			}
+		}
+
+		cells, err := adaptivecard.NewTableCellsWithTextBlock(items)
+		if err != nil {
+			return adaptivecard.Element{}, "", fmt.Errorf("failed to create table row %d: %w", i+1, err)
+		}
+
+		for _, cell := range cells {
+			for _, item := range cell.Items {
+				item.Wrap = true
+			}
+		}
+
+		encoded, err := json.Marshal(cells)
+		if err != nil {
+			return adaptivecard.Element{}, "", fmt.Errorf("failed to encode table row %d: %w", i+1, err)
+		}
+
+		size += len(encoded)
+		if size > tableSizeLimit {
+			if len(rows) == 0 || (cfg.TableHeader && len(rows) == 1) {
+				return adaptivecard.Element{}, "", fmt.Errorf(
+					"%w: table row %d is too large to display",
+					ErrMessageTooLarge,
+					i+1,
+				)
+			}
+
+			break
+		}
+
+		rows = append(rows, cells)
+	}
+
+	table, err := adaptivecard.NewTableFromTableCells(rows, columns, cfg.TableHeader, cfg.TableGrid)
+	if err != nil {
+		return adaptivecard.Element{}, "", fmt.Errorf("failed to create table: %w", err)
+	}
+
+	shownRows := len(rows)
+	if cfg.TableHeader {
+		shownRows--
+	}
+
+	var omitted []string
+	if shownRows < dataRows {
+		omitted = append(omitted, fmt.Sprintf("%d of %d rows", shownRows, dataRows))
+	}
+	if columns < totalColumns {
+		omitted = append(omitted, fmt.Sprintf("%d of %d columns", columns, totalColumns))
+	}
+
+	var note string
+	if len(omitted) > 0 {
+		note = "Showing " + strings.Join(omitted, " and ") + "."
+	}
+
+	return table, note, nil
+}
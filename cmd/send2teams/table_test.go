@@ -0,0 +1,177 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/send2teams/internal/config"
+)
+
+// tableRows returns count rows of the given number of columns.
+func tableRows(count int, columns int) [][]string {
+	rows := make([][]string, 0, count)
+	for i := 0; i < count; i++ {
+		row := make([]string, 0, columns)
+		for j := 0; j < columns; j++ {
+			row = append(row, fmt.Sprintf("r%dc%d", i+1, j+1))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func TestNewDataTableLimits(t *testing.T) {
+	tests := map[string]struct {
+		table           [][]string
+		header          bool
+		maxColumns      int
+		maxRows         int
+		expectedRows    int
+		expectedColumns int
+		expectedNote    string
+	}{
+		"within limits": {
+			table:           tableRows(3, 2),
+			maxColumns:      2,
+			maxRows:         3,
+			expectedRows:    3,
+			expectedColumns: 2,
+		},
+		"rows omitted": {
+			table:           tableRows(5, 2),
+			maxColumns:      10,
+			maxRows:         3,
+			expectedRows:    3,
+			expectedColumns: 2,
+			expectedNote:    "Showing 3 of 5 rows.",
+		},
+		"header row not counted": {
+			table:           tableRows(5, 2),
+			header:          true,
+			maxColumns:      10,
+			maxRows:         3,
+			expectedRows:    4,
+			expectedColumns: 2,
+			expectedNote:    "Showing 3 of 4 rows.",
+		},
+		"header row with rows at limit": {
+			table:           tableRows(4, 2),
+			header:          true,
+			maxColumns:      10,
+			maxRows:         3,
+			expectedRows:    4,
+			expectedColumns: 2,
+		},
+		"columns omitted": {
+			table:           tableRows(2, 5),
+			maxColumns:      3,
+			maxRows:         10,
+			expectedRows:    2,
+			expectedColumns: 3,
+			expectedNote:    "Showing 3 of 5 columns.",
+		},
+		"rows and columns omitted": {
+			table:           tableRows(5, 5),
+			maxColumns:      1,
+			maxRows:         1,
+			expectedRows:    1,
+			expectedColumns: 1,
+			expectedNote:    "Showing 1 of 5 rows and 1 of 5 columns.",
+		},
+		"ragged rows padded to widest row": {
+			table:           [][]string{{"a"}, {"b", "c", "d"}, {}},
+			maxColumns:      10,
+			maxRows:         10,
+			expectedRows:    3,
+			expectedColumns: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Config{
+				Table:           tt.table,
+				TableHeader:     tt.header,
+				TableMaxColumns: tt.maxColumns,
+				TableMaxRows:    tt.maxRows,
+			}
+
+			table, note, err := newDataTable(cfg)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if len(table.Rows) != tt.expectedRows {
+				t.Errorf("got %d rows; expected %d", len(table.Rows), tt.expectedRows)
+			}
+
+			if len(table.Columns) != tt.expectedColumns {
+				t.Errorf("got %d columns; expected %d", len(table.Columns), tt.expectedColumns)
+			}
+
+			for i, row := range table.Rows {
+				if len(row.Cells) != tt.expectedColumns {
+					t.Errorf("got %d cells in row %d; expected %d", len(row.Cells), i+1, tt.expectedColumns)
+				}
+			}
+
+			if note != tt.expectedNote {
+				t.Errorf("got note %q; expected %q", note, tt.expectedNote)
+			}
+		})
+	}
+}
+
+func TestNewDataTableSizeLimit(t *testing.T) {
+	// Each row is large enough that only a few rows fit within the table
+	// size limit.
+	cell := strings.Repeat("x", tableSizeLimit/5)
+
+	rows := make([][]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		rows = append(rows, []string{cell})
+	}
+
+	cfg := &config.Config{
+		Table:           rows,
+		TableMaxColumns: 10,
+		TableMaxRows:    100,
+	}
+
+	table, note, err := newDataTable(cfg)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if len(table.Rows) == 0 || len(table.Rows) >= len(rows) {
+		t.Fatalf("got %d rows; expected rows beyond the size limit to be omitted", len(table.Rows))
+	}
+
+	expectedNote := fmt.Sprintf("Showing %d of %d rows.", len(table.Rows), len(rows))
+	if note != expectedNote {
+		t.Errorf("got note %q; expected %q", note, expectedNote)
+	}
+}
+
+func TestNewDataTableRowTooLarge(t *testing.T) {
+	cfg := &config.Config{
+		Table:           [][]string{{"name"}, {strings.Repeat("x", tableSizeLimit)}},
+		TableHeader:     true,
+		TableMaxColumns: 10,
+		TableMaxRows:    10,
+	}
+
+	if _, _, err := newDataTable(cfg); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("got %v; expected error %q", err, ErrMessageTooLarge)
+	}
+}
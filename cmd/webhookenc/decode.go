@@ -0,0 +1,310 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+// decodeCommand is the subcommand used to decode and inspect an encoded (or
+// encrypted) webhook URL.
+const decodeCommand string = "decode"
+
+const (
+	showSecretsFlagHelp   = "Whether webhook URL secrets (e.g., the sig signature of Power Automate workflow URLs) should be shown instead of being masked."
+	decodeKeyFileFlagHelp = "The path to a file containing the key used to decrypt an encrypted webhook URL."
+)
+
+// encryptedSegmentPattern matches the characters permitted in a segment of
+// an encrypted webhook URL (unpadded base64url).
+var encryptedSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// decodeReport records the results of decoding a webhook URL.
+type decodeReport struct {
+	// webhookURL is the decoded (or decrypted) webhook URL.
+	webhookURL string
+
+	// problems are issues which prevent the webhook URL from being used or
+	// which indicate that it was not provided as intended.
+	problems []string
+
+	// notes are informational findings which do not prevent the webhook URL
+	// from being used.
+	notes []string
+
+	// showSecrets indicates whether webhook URL secrets are shown in the
+	// report.
+	showSecrets bool
+}
+
+// problemf records a problem using the given format and arguments.
+func (dr *decodeReport) problemf(format string, v ...interface{}) {
+	dr.problems = append(dr.problems, dr.redact(fmt.Sprintf(format, v...)))
+}
+
+// notef records a note using the given format and arguments.
+func (dr *decodeReport) notef(format string, v ...interface{}) {
+	dr.notes = append(dr.notes, dr.redact(fmt.Sprintf(format, v...)))
+}
+
+// redact masks webhook URL secrets in the given text unless secrets should
+// be shown.
+func (dr *decodeReport) redact(text string) string {
+	if dr.showSecrets {
+		return text
+	}
+
+	return webhookurl.RedactText(text)
+}
+
+// runDecode decodes the webhook URL segments given as arguments to the
+// decode subcommand and writes a report of the results to stdout. Segments
+// may be given as separate arguments or as a single comma separated
+// argument. The application exit code is returned; a non-zero exit code
+// indicates that problems were found.
+func runDecode(args []string) int {
+	fs := flag.NewFlagSet(decodeCommand, flag.ExitOnError)
+	showSecrets := fs.Bool("show-secrets", false, showSecretsFlagHelp)
+	keyFile := fs.String("key-file", "", decodeKeyFileFlagHelp)
+
+	// flag.ExitOnError is used, so an error is never returned.
+	_ = fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		appBasename := filepath.Base(os.Args[0])
+
+		fmt.Println("Error: Please provide the webhook URL segments to decode.")
+		fmt.Printf("\nExample:\n\n")
+		fmt.Printf("%s %s 'SEGMENT1,SEGMENT2,SEGMENT3'\n", appBasename, decodeCommand)
+		fmt.Printf("%s %s SEGMENT1 SEGMENT2 SEGMENT3\n", appBasename, decodeCommand)
+
+		return 1
+	}
+
+	var segments []string
+	for _, arg := range fs.Args() {
+		segments = append(segments, strings.Split(arg, ",")...)
+	}
+
+	report := decodeSegments(os.Stdout, segments, *keyFile, *showSecrets)
+	if len(report.problems) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// decodeSegments decodes (or decrypts using the key from the given key
+// file) the given webhook URL segments, writing the encoding of each
+// segment, the decoded webhook URL and any notes or problems found to w.
+// Webhook URL secrets are masked unless showSecrets is true. The report of
+// the results is returned.
+func decodeSegments(w io.Writer, segments []string, keyFile string, showSecrets bool) decodeReport {
+	report := decodeReport{showSecrets: showSecrets}
+
+	fmt.Fprintf(w, "Segments (%d):\n\n", len(segments))
+
+	if webhookurl.IsEncrypted(segments[0]) {
+		report.inspectEncryptedSegments(w, segments)
+		report.decrypt(segments, keyFile)
+	} else {
+		report.inspectBase64Segments(w, segments)
+		report.decode(segments)
+	}
+
+	if report.webhookURL != "" {
+		report.validate()
+
+		displayURL := report.webhookURL
+		if !report.showSecrets {
+			displayURL = webhookurl.Redact(displayURL)
+		}
+
+		fmt.Fprintf(w, "\nDecoded URL:\n\n%s\n", displayURL)
+	}
+
+	if len(report.notes) > 0 {
+		fmt.Fprintf(w, "\nNotes:\n\n")
+		for _, note := range report.notes {
+			fmt.Fprintf(w, "  - %s\n", note)
+		}
+	}
+
+	if len(report.problems) == 0 {
+		fmt.Fprintf(w, "\nNo problems found.\n")
+
+		return report
+	}
+
+	fmt.Fprintf(w, "\nProblems:\n\n")
+	for _, problem := range report.problems {
+		fmt.Fprintf(w, "  - %s\n", problem)
+	}
+
+	return report
+}
+
+// inspectSegmentWhitespace records a problem for empty segments and for
+// segments containing whitespace, returning the segment with surrounding
+// whitespace removed.
+func (dr *decodeReport) inspectSegmentWhitespace(label string, segment string) string {
+	trimmed := strings.TrimSpace(segment)
+
+	switch {
+	case trimmed == "":
+		dr.problemf("%s is empty (check for missing values or extra commas)", label)
+	case trimmed != segment:
+		dr.problemf("%s has leading or trailing whitespace", label)
+	case strings.ContainsFunc(trimmed, unicode.IsSpace):
+		dr.problemf("%s contains whitespace", label)
+	}
+
+	return trimmed
+}
+
+// inspectBase64Segments writes the RFC 4648 base64 variant used by each
+// base64 encoded segment to w.
+func (dr *decodeReport) inspectBase64Segments(w io.Writer, segments []string) {
+	for i, segment := range segments {
+		label := fmt.Sprintf("segment %d of %d", i+1, len(segments))
+		trimmed := dr.inspectSegmentWhitespace(label, segment)
+
+		var description string
+		variants := webhookurl.Base64Variants(trimmed)
+
+		switch {
+		case trimmed == "":
+			description = "empty"
+		case len(variants) == 0:
+			description = "not valid base64"
+		case len(variants) > 1:
+			description = fmt.Sprintf("%s base64 (also valid as %s)", variants[0], strings.Join(variants[1:], ", "))
+		default:
+			description = variants[0] + " base64"
+		}
+
+		fmt.Fprintf(w, "  %s: %d characters, %s\n", label, len(trimmed), description)
+	}
+}
+
+// decode decodes the given base64 encoded segments in the same way as
+// send2teams.
+func (dr *decodeReport) decode(segments []string) {
+	var invalid []string
+	for i, segment := range segments {
+		if len(webhookurl.Base64Variants(segment)) == 0 {
+			invalid = append(invalid, fmt.Sprintf("segment %d of %d", i+1, len(segments)))
+		}
+	}
+
+	decoded, err := webhookurl.DecodeBase64(strings.Join(segments, ","))
+	if err != nil {
+		for _, label := range invalid {
+			dr.problemf("%s is not valid base64 using any supported RFC 4648 variant", label)
+		}
+		dr.problemf("failed to decode webhook URL: %v", err)
+
+		return
+	}
+
+	if len(invalid) > 0 && len(segments) > 1 {
+		dr.notef("segments are not individually valid; decoded as a single base64 string split across segments")
+	}
+
+	webhookURL := string(decoded)
+	if trimmed := strings.TrimSpace(webhookURL); trimmed != webhookURL {
+		dr.notef("decoded URL has leading or trailing whitespace (e.g., a newline encoded by echo without -n) which send2teams ignores")
+		webhookURL = trimmed
+	}
+
+	if strings.ContainsFunc(webhookURL, func(r rune) bool { return !unicode.IsPrint(r) || unicode.IsSpace(r) }) {
+		dr.problemf("decoded URL contains whitespace or non-printable characters")
+	}
+
+	dr.webhookURL = webhookURL
+}
+
+// inspectEncryptedSegments writes whether each segment of an encrypted
+// webhook URL is composed of valid characters to w.
+func (dr *decodeReport) inspectEncryptedSegments(w io.Writer, segments []string) {
+	for i, segment := range segments {
+		label := fmt.Sprintf("segment %d of %d", i+1, len(segments))
+		trimmed := dr.inspectSegmentWhitespace(label, segment)
+
+		value := trimmed
+		description := "encrypted, alternate raw (unpadded base64url) characters"
+		if i == 0 {
+			value = strings.TrimPrefix(trimmed, webhookurl.EncryptedPrefix)
+			description = fmt.Sprintf("encrypted (%s prefix), alternate raw (unpadded base64url) characters", webhookurl.EncryptedPrefix)
+		}
+
+		switch {
+		case trimmed == "":
+			description = "empty"
+		case i > 0 && webhookurl.IsEncrypted(trimmed):
+			description = "unexpected encrypted webhook URL prefix"
+			dr.problemf("%s begins with the %s prefix (only the first segment should)", label, webhookurl.EncryptedPrefix)
+		case !encryptedSegmentPattern.MatchString(value):
+			description = "invalid characters"
+			dr.problemf("%s contains characters which are not valid in an encrypted webhook URL", label)
+		}
+
+		fmt.Fprintf(w, "  %s: %d characters, %s\n", label, len(trimmed), description)
+	}
+}
+
+// decrypt decrypts the given encrypted webhook URL segments using the key
+// from the given key file.
+func (dr *decodeReport) decrypt(segments []string, keyFile string) {
+	if keyFile == "" {
+		dr.problemf("webhook URL is encrypted; use the key-file flag to decrypt it")
+
+		return
+	}
+
+	key, err := readKeyFile(keyFile)
+	if err != nil {
+		dr.problemf("failed to load key: %v", err)
+
+		return
+	}
+
+	webhookURL, err := webhookurl.Decrypt(strings.Join(segments, ","), key)
+	if err != nil {
+		dr.problemf("%v (check that all segments are present, in order and that the correct key is used)", err)
+
+		return
+	}
+
+	dr.webhookURL = webhookURL
+}
+
+// validate asserts that the decoded webhook URL is a valid URL which passes
+// the webhook URL validation applied by send2teams.
+func (dr *decodeReport) validate() {
+	if _, err := url.ParseRequestURI(dr.webhookURL); err != nil {
+		dr.problemf("decoded value is not a valid URL: %v", err)
+
+		return
+	}
+
+	if err := goteamsnotify.NewTeamsClient().ValidateWebhook(dr.webhookURL); err != nil {
+		dr.problemf("decoded URL fails webhook URL validation: %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+// testWorkflowURLSig is the sig query parameter value of testWorkflowURL.
+const testWorkflowURLSig string = "vqF0En-Z0ucuRTM01o2GuhMH3hKKkN2bOmlM31zaA"
+
+func TestDecodeSegments(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, webhookurl.KeySize)
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+
+	encrypted, err := webhookurl.Encrypt(testWorkflowURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+	encryptedSplit := len(encrypted) / 2
+
+	standard := base64.StdEncoding.EncodeToString([]byte(testWorkflowURL))
+
+	tests := map[string]struct {
+		segments         []string
+		keyFileContents  string
+		showSecrets      bool
+		expectedURL      string
+		expectedOutput   []string
+		expectedNotes    []string
+		expectedProblems []string
+	}{
+		"standard": {
+			segments:       []string{standard},
+			expectedURL:    testWorkflowURL,
+			expectedOutput: []string{"segment 1 of 1: 340 characters, standard base64 (also valid as standard raw (unpadded))"},
+		},
+		"standard padded": {
+			segments:       []string{base64.StdEncoding.EncodeToString([]byte(testWorkflowURL + "0"))},
+			expectedURL:    testWorkflowURL + "0",
+			expectedOutput: []string{"segment 1 of 1: 344 characters, standard base64\n"},
+		},
+		"URL-safe": {
+			segments:       []string{base64.URLEncoding.EncodeToString([]byte(testWorkflowURL))},
+			expectedURL:    testWorkflowURL,
+			expectedOutput: []string{"alternate (base64url) base64 (also valid as alternate raw (unpadded base64url))"},
+		},
+		"raw URL-safe": {
+			segments:       []string{base64.RawURLEncoding.EncodeToString([]byte(testWorkflowURL + "0"))},
+			expectedURL:    testWorkflowURL + "0",
+			expectedOutput: []string{"segment 1 of 1: 342 characters, alternate raw (unpadded base64url) base64\n"},
+		},
+		"multiple segments": {
+			segments:    splitSegments(base64.RawURLEncoding.EncodeToString([]byte(testWorkflowURL)), 120),
+			expectedURL: testWorkflowURL,
+			expectedOutput: []string{
+				"Segments (3):",
+				"segment 1 of 3: 120 characters, standard base64",
+				"segment 2 of 3: 120 characters, alternate (base64url) base64",
+				"segment 3 of 3: 100 characters, ",
+			},
+		},
+		"segments not individually valid": {
+			segments:      []string{standard[:101], standard[101:]},
+			expectedURL:   testWorkflowURL,
+			expectedNotes: []string{"segments are not individually valid"},
+		},
+		"decoded URL with trailing newline": {
+			segments:      []string{base64.StdEncoding.EncodeToString([]byte(testWorkflowURL + "\n"))},
+			expectedURL:   testWorkflowURL,
+			expectedNotes: []string{"decoded URL has leading or trailing whitespace"},
+		},
+		"segment with surrounding whitespace": {
+			segments:         []string{standard[:200], " " + standard[200:] + "\n"},
+			expectedProblems: []string{"segment 2 of 2 has leading or trailing whitespace"},
+		},
+		"segment containing whitespace": {
+			segments:         []string{standard[:200] + " " + standard[200:]},
+			expectedProblems: []string{"segment 1 of 1 contains whitespace"},
+		},
+		"empty segment": {
+			segments:         []string{standard[:200], "", standard[200:]},
+			expectedOutput:   []string{"segment 2 of 3: 0 characters, empty"},
+			expectedProblems: []string{"segment 2 of 3 is empty"},
+		},
+		"invalid segment": {
+			segments:       []string{standard[:200], "not*base64"},
+			expectedOutput: []string{"segment 2 of 2: 10 characters, not valid base64"},
+			expectedProblems: []string{
+				"segment 2 of 2 is not valid base64",
+				"failed to decode webhook URL",
+			},
+		},
+		"decoded value not a URL": {
+			segments:         []string{base64.StdEncoding.EncodeToString([]byte("example.com/webhook"))},
+			expectedURL:      "example.com/webhook",
+			expectedProblems: []string{"decoded value is not a valid URL"},
+		},
+		"encrypted with key file": {
+			segments:        []string{encrypted[:encryptedSplit], encrypted[encryptedSplit:]},
+			keyFileContents: encodedKey + "\n",
+			expectedURL:     testWorkflowURL,
+			expectedOutput: []string{
+				"segment 1 of 2: " + strconv.Itoa(encryptedSplit) + " characters, encrypted (s2t-enc-v1: prefix)",
+				"segment 2 of 2: " + strconv.Itoa(len(encrypted)-encryptedSplit) + " characters, encrypted, alternate raw",
+			},
+		},
+		"encrypted without key file": {
+			segments:         []string{encrypted},
+			expectedProblems: []string{"webhook URL is encrypted; use the key-file flag to decrypt it"},
+		},
+		"encrypted with wrong key": {
+			segments:         []string{encrypted},
+			keyFileContents:  base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, webhookurl.KeySize)),
+			expectedProblems: []string{"check that all segments are present"},
+		},
+		"encrypted with invalid key file": {
+			segments:         []string{encrypted},
+			keyFileContents:  "not a key",
+			expectedProblems: []string{"failed to load key"},
+		},
+		"encrypted segments out of order": {
+			segments:         []string{encrypted[:encryptedSplit], encrypted},
+			keyFileContents:  encodedKey,
+			expectedProblems: []string{"segment 2 of 2 begins with the s2t-enc-v1: prefix"},
+		},
+		"encrypted segment with invalid characters": {
+			segments:         []string{encrypted[:encryptedSplit], "+" + encrypted[encryptedSplit:]},
+			keyFileContents:  encodedKey,
+			expectedProblems: []string{"segment 2 of 2 contains characters which are not valid"},
+		},
+		"secrets shown": {
+			segments:       []string{standard},
+			showSecrets:    true,
+			expectedURL:    testWorkflowURL,
+			expectedOutput: []string{"Decoded URL:\n\n" + testWorkflowURL + "\n"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var keyFile string
+			if tt.keyFileContents != "" {
+				keyFile = filepath.Join(t.TempDir(), "webhook.key")
+				if err := os.WriteFile(keyFile, []byte(tt.keyFileContents), 0o600); err != nil {
+					t.Fatalf("failed to write key file: %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			report := decodeSegments(&buf, tt.segments, keyFile, tt.showSecrets)
+			output := buf.String()
+
+			if tt.expectedURL != "" && report.webhookURL != tt.expectedURL {
+				t.Errorf("got URL %q; expected %q", report.webhookURL, tt.expectedURL)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !strings.Contains(output, expected) {
+					t.Errorf("got output %q; expected it to contain %q", output, expected)
+				}
+			}
+
+			// The signature is masked in the decoded URL, notes and problems
+			// unless secrets should be shown.
+			if strings.Contains(output, testWorkflowURLSig) != tt.showSecrets {
+				t.Errorf("got output %q; expected sig to be shown: %t", output, tt.showSecrets)
+			}
+
+			assertFindings(t, "note", report.notes, tt.expectedNotes)
+			assertFindings(t, "problem", report.problems, tt.expectedProblems)
+
+			if len(tt.expectedProblems) == 0 && !strings.Contains(output, "No problems found.") {
+				t.Errorf("got output %q; expected no problems to be reported", output)
+			}
+		})
+	}
+}
+
+// assertFindings asserts that each expected finding is contained in one of
+// the given findings (e.g., notes or problems) and that no findings are
+// present if none are expected.
+func assertFindings(t *testing.T, kind string, findings []string, expected []string) {
+	t.Helper()
+
+	if len(expected) == 0 && len(findings) > 0 {
+		t.Errorf("got %ss %q; expected none", kind, findings)
+	}
+
+	for _, want := range expected {
+		var found bool
+		for _, finding := range findings {
+			if strings.Contains(finding, want) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("got %ss %q; expected one to contain %q", kind, findings, want)
+		}
+	}
+}
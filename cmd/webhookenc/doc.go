@@ -9,7 +9,9 @@
 // intended for use by Nagios admins who may wish to encode a webhook URL for
 // inclusion in a `Custom Object Variable` or a `User Macro` where
 // sanitization would strip out required `&` characters used to separate URL
-// query parameters in webhook URLs.
+// query parameters in webhook URLs. Webhook URLs may optionally be encrypted
+// using a local key file for confidentiality. The decode subcommand decodes
+// (or decrypts) and inspects previously encoded webhook URLs.
 //
 // See our [GitHub repo]:
 //
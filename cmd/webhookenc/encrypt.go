@@ -0,0 +1,121 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+// readKeyFile reads the key used to encrypt or decrypt webhook URLs from the
+// given key file.
+func readKeyFile(keyFile string) ([]byte, error) {
+	// #nosec G304 -- file path is intentionally provided by the user
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	return webhookurl.ParseKey(data)
+}
+
+// validateEncryptedResults asserts that the encrypted segments (when
+// combined) decrypt back to the original URL and that each segment fits
+// within the maximum segment length, otherwise the application is aborted.
+func validateEncryptedResults(segments []string, key []byte, originalURL string, maxSegmentLength int) {
+	for i, segment := range segments {
+		if len(segment) > maxSegmentLength {
+			panic(fmt.Sprintf("Error: encrypted segment %d exceeds %d characters", i+1, maxSegmentLength))
+		}
+	}
+
+	decryptedURL, err := webhookurl.Decrypt(strings.Join(segments, ","), key)
+	if err != nil {
+		panic(err)
+	}
+
+	if decryptedURL != originalURL {
+		panic("Error: Failed to decrypt encrypted URL back to original URL")
+	}
+}
+
+// encryptURL encrypts the given webhook URL using the key from the given key
+// file and emits the result split into segments which fit within the given
+// maximum segment length (e.g., the Nagios XI DB field limit for Custom
+// Object Variables).
+func encryptURL(rawURL string, keyFile string, maxSegmentLength int) {
+	if keyFile == "" {
+		fmt.Println("Error: Please provide the key file used to encrypt the webhook URL via the key-file flag.")
+		fmt.Printf("\nA new key may be generated like so:\n\n")
+		fmt.Printf("  (umask 077 && openssl rand -base64 %d > /etc/send2teams/key)\n", webhookurl.KeySize)
+		os.Exit(1)
+	}
+
+	key, err := readKeyFile(keyFile)
+	if err != nil {
+		panic(err)
+	}
+
+	encrypted, err := webhookurl.Encrypt(rawURL, key)
+	if err != nil {
+		panic(err)
+	}
+
+	segments := splitSegments(encrypted, maxSegmentLength)
+
+	// Hard stop if the segments don't decrypt back to the original URL.
+	validateEncryptedResults(segments, key, rawURL, maxSegmentLength)
+
+	varNames := segmentVarNames(len(segments))
+
+	fmt.Printf("Provided URL:\n%v\n\nEncrypts to these Custom Object Variables:\n\n", rawURL)
+	for i, segment := range segments {
+		fmt.Printf("    %s\n    %s\n    %d characters\n\n", varNames[i], segment, len(segment))
+	}
+
+	fmt.Println("Copy/paste into Nagios contact entry config:")
+	fmt.Println()
+	for i, segment := range segments {
+		fmt.Printf("    %s    %s\n", varNames[i], segment)
+	}
+	fmt.Println()
+
+	fmt.Printf(
+		"NOTE: We split into multiple encrypted values of at most %d chars to comply with field length limitations (e.g., Nagios XI DB field limitations (%d chars) for Custom Object Variables).\n",
+		maxSegmentLength,
+		nagiosDBCustomObjectVariablesMaxFieldLength,
+	)
+
+	combined := strings.Join(segments, ",")
+
+	fmt.Printf(
+		"\nCombined (comma separated) input string for testing with send2teams:\n\n'%s'\n",
+		combined,
+	)
+
+	fmt.Printf(
+		`
+Use like so (the key file must be available to send2teams):
+
+  ./send2teams \
+    --silent \
+    --channel "Alerts" \
+    --team "Support" \
+    --message "System XYZ is down!" \
+    --title "System outage alert" \
+    --sender "Nagios" \
+    --key-file "%s" \
+    --url "%s"
+`,
+		keyFile,
+		combined,
+	)
+}
@@ -10,6 +10,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net/url"
 	"os"
@@ -26,17 +27,30 @@ const (
 	nagiosDBCustomObjectVariablesMaxFieldLength int = 255
 )
 
+const (
+	encryptFlagHelp          = "Whether the webhook URL should be encrypted (using the key from the key file) instead of base64 encoded."
+	keyFileFlagHelp          = "The path to a file containing the base64 encoded 256-bit key used to encrypt the webhook URL. Required when encrypting."
+	maxSegmentLengthFlagHelp = "The maximum number of characters in each encoded (or encrypted) segment. The webhook URL is split into as many segments as needed. Use the field length limit of the platform where segments are stored."
+)
+
 // validateResults asserts that:
 //
 //  1. that the raw segments when combined match the original URL
 //  2. the decoded URL matches the original URL
+//  3. each encoded segment fits within the maximum segment length
 //
 // otherwise the application is aborted.
-func validateResults(encodedURL string, rawSegments []string, originalURL string) {
+func validateResults(encodedURL string, rawSegments []string, encodedSegments []string, originalURL string, maxSegmentLength int) {
 	if strings.Join(rawSegments, "") != originalURL {
 		panic("Error: parsed/reconstructed URL does not match original URL")
 	}
 
+	for i, encodedSegment := range encodedSegments {
+		if utf8.RuneCountInString(encodedSegment) > maxSegmentLength {
+			panic(fmt.Sprintf("Error: encoded segment %d exceeds %d characters", i+1, maxSegmentLength))
+		}
+	}
+
 	decodedURL, err := webhookurl.DecodeBase64(encodedURL)
 	if err != nil {
 		panic(err)
@@ -53,97 +67,102 @@ func validateResults(encodedURL string, rawSegments []string, originalURL string
 func main() {
 	exampleURL := `https://defaultccb6deedbd294b388979d72780f62d.3b.environment.api.powerplatform.com:443/powerautomate/automations/direct/workflows/1d3ada0d8a334289b6bd8bfa6ee63bb0/triggers/manual/paths/invoke?api-version=1&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX`
 
-	if len(os.Args) < 2 || strings.TrimSpace(os.Args[1]) == "" {
+	// Decode and inspect previously encoded webhook URLs if requested.
+	if len(os.Args) > 1 && os.Args[1] == decodeCommand {
+		os.Exit(runDecode(os.Args[2:]))
+	}
+
+	var encrypt bool
+	var keyFile string
+	var maxSegmentLength int
+
+	flag.BoolVar(&encrypt, "encrypt", false, encryptFlagHelp)
+	flag.StringVar(&keyFile, "key-file", "", keyFileFlagHelp)
+	flag.IntVar(&maxSegmentLength, "max-segment-length", nagiosDBCustomObjectVariablesMaxFieldLength, maxSegmentLengthFlagHelp)
+	flag.Parse()
+
+	if flag.NArg() < 1 || strings.TrimSpace(flag.Arg(0)) == "" {
 		appBasename := filepath.Base(os.Args[0])
 
 		fmt.Println("Error: Please provide input webhook URL for encoding.")
 		fmt.Printf("\nExample:\n\n")
 		fmt.Printf("%s '%s'\n", appBasename, exampleURL)
+		fmt.Printf("%s --encrypt --key-file /etc/send2teams/key '%s'\n", appBasename, exampleURL)
+		fmt.Printf("\nTo decode and inspect an encoded webhook URL:\n\n")
+		fmt.Printf("%s %s 'SEGMENT1,SEGMENT2,SEGMENT3'\n", appBasename, decodeCommand)
 		return
 	}
 
-	rawURL := strings.TrimSpace(os.Args[1])
-
-	u, err := url.ParseRequestURI(rawURL)
-	if err != nil {
-		panic(err)
+	if keyFile != "" && !encrypt {
+		fmt.Println("Error: The key-file flag is only used with the encrypt flag.")
+		os.Exit(1)
 	}
 
-	part1 := fmt.Sprintf(
-		`%v://%v`,
-		u.Scheme,
-		u.Host,
-	)
-
-	part2 := fmt.Sprintf(
-		`%v?`,
-		u.Path,
-	)
-
-	part3 := u.RawQuery
-
-	verboseOutputTemplate := `
-    _powerautomateworkflowurl_part1of3
-    %v
-    %d base64 encoded characters
-
-    _powerautomateworkflowurl_part2of3
-    %v
-    %d base64 encoded characters
+	if maxSegmentLength < minSegmentLength {
+		fmt.Printf("Error: The max-segment-length flag must be at least %d characters.\n", minSegmentLength)
+		os.Exit(1)
+	}
 
-    _powerautomateworkflowurl_part3of3
-    %v
-    %d base64 encoded characters
+	rawURL := strings.TrimSpace(flag.Arg(0))
 
-`
+	if _, err := url.ParseRequestURI(rawURL); err != nil {
+		panic(err)
+	}
 
-	simpleOutputTemplate := `
-    _powerautomateworkflowurl_part1of3    %v
-    _powerautomateworkflowurl_part2of3    %v
-    _powerautomateworkflowurl_part3of3    %v
+	if encrypt {
+		encryptURL(rawURL, keyFile, maxSegmentLength)
+		return
+	}
 
-`
+	// Split into (scheme and host, path, query) parts, splitting each part
+	// further as needed so that each encoded segment fits within the limit.
+	rawSegments := splitURL(rawURL, maxSegmentLength)
 
 	// encode values
-	part1Encoded := webhookurl.EncodeToBase64String([]byte(part1))
-	part2Encoded := webhookurl.EncodeToBase64String([]byte(part2))
-	part3Encoded := webhookurl.EncodeToBase64String([]byte(part3))
+	encodedSegments := make([]string, 0, len(rawSegments))
+	for _, rawSegment := range rawSegments {
+		encodedSegments = append(encodedSegments, webhookurl.EncodeToBase64String([]byte(rawSegment)))
+	}
 
-	combinedBase64, err := webhookurl.JoinBase64Segments(part1Encoded, part2Encoded, part3Encoded)
+	combinedBase64, err := webhookurl.JoinBase64Segments(encodedSegments...)
 	if err != nil {
 		panic(err)
 	}
 
-	// Hard stop if new URL doesn't decode back to the original URL.
-	validateResults(combinedBase64, []string{part1, part2, part3}, rawURL)
+	// Hard stop if new URL doesn't decode back to the original URL or if a
+	// segment is too long.
+	validateResults(combinedBase64, rawSegments, encodedSegments, rawURL, maxSegmentLength)
 
-	fmt.Printf("Provided URL:\n%v\n\nBreaks down to these Custom Object Variables:\n", rawURL)
-	fmt.Printf(
-		verboseOutputTemplate,
-		part1Encoded,
-		utf8.RuneCountInString(part1Encoded),
-		part2Encoded,
-		utf8.RuneCountInString(part2Encoded),
-		part3Encoded,
-		utf8.RuneCountInString(part3Encoded),
-	)
+	varNames := segmentVarNames(len(encodedSegments))
+
+	fmt.Printf("Provided URL:\n%v\n\nBreaks down to these Custom Object Variables:\n\n", rawURL)
+	for i, encodedSegment := range encodedSegments {
+		fmt.Printf(
+			"    %s\n    %v\n    %d base64 encoded characters\n\n",
+			varNames[i],
+			encodedSegment,
+			utf8.RuneCountInString(encodedSegment),
+		)
+	}
 
 	fmt.Println("Copy/paste into Nagios contact entry config:")
-	fmt.Printf(
-		simpleOutputTemplate,
-		part1Encoded,
-		part2Encoded,
-		part3Encoded,
-	)
+	fmt.Println()
+	for i, encodedSegment := range encodedSegments {
+		fmt.Printf("    %s    %v\n", varNames[i], encodedSegment)
+	}
+	fmt.Println()
 
 	fmt.Printf(
-		"NOTE: We split into multiple base64 encoded values to comply with Nagios XI DB field limitations (%d chars) for Custom Object Variables.\n",
+		"NOTE: We split into multiple base64 encoded values of at most %d chars to comply with field length limitations (e.g., Nagios XI DB field limitations (%d chars) for Custom Object Variables).\n",
+		maxSegmentLength,
 		nagiosDBCustomObjectVariablesMaxFieldLength,
 	)
 
+	combinedSegments := strings.Join(encodedSegments, ",")
+
 	fmt.Printf(
-		"\nCombined (comma separated) input string for testing with send2teams:\n\n'%s,%s,%s'\n",
-		part1Encoded, part2Encoded, part3Encoded,
+		"\nCombined (comma separated) input string for testing with send2teams:\n\n'%s'\n",
+		combinedSegments,
 	)
 
 	fmt.Printf(
@@ -157,9 +176,9 @@ Use like so:
     --message "System XYZ is down!" \
     --title "System outage alert" \
     --sender "Nagios" \
-    --url "%s,%s,%s"
+    --url "%s"
 `,
-		part1Encoded, part2Encoded, part3Encoded,
+		combinedSegments,
 	)
 
 	fmt.Printf(
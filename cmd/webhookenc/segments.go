@@ -0,0 +1,88 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minSegmentLength is the minimum supported maximum segment length. This
+// leaves room for the encrypted webhook URL prefix in the first segment.
+const minSegmentLength int = 16
+
+// segmentVarNamePrefix is the prefix of the Custom Object Variable names
+// emitted for each segment.
+const segmentVarNamePrefix string = "_powerautomateworkflowurl"
+
+// segmentVarNames returns the Custom Object Variable names for the given
+// number of segments (e.g., _powerautomateworkflowurl_part1of3).
+func segmentVarNames(count int) []string {
+	names := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		names = append(names, fmt.Sprintf("%s_part%dof%d", segmentVarNamePrefix, i, count))
+	}
+
+	return names
+}
+
+// urlParts splits the given URL into the scheme and host, the path (along
+// with the query separator) and the query. Parts which would be empty are
+// omitted. The parts when combined always match the given URL.
+func urlParts(rawURL string) []string {
+	var parts []string
+	rest := rawURL
+
+	if i := strings.Index(rest, "://"); i >= 0 {
+		if j := strings.IndexAny(rest[i+len("://"):], "/?#"); j >= 0 {
+			end := i + len("://") + j
+			parts = append(parts, rest[:end])
+			rest = rest[end:]
+		}
+	}
+
+	if i := strings.Index(rest, "?"); i > 0 {
+		parts = append(parts, rest[:i+1])
+		rest = rest[i+1:]
+	}
+
+	if rest != "" {
+		parts = append(parts, rest)
+	}
+
+	return parts
+}
+
+// splitURL splits the given URL into raw segments whose unpadded base64url
+// encoding is at most maxLength characters. The URL is split into its scheme
+// and host, path and query parts and each part is split further as needed.
+func splitURL(rawURL string, maxLength int) []string {
+	// Each 3 bytes are encoded as 4 characters, so this many bytes always
+	// encode to at most maxLength characters.
+	maxBytes := maxLength * 3 / 4
+
+	var segments []string
+	for _, part := range urlParts(rawURL) {
+		segments = append(segments, splitSegments(part, maxBytes)...)
+	}
+
+	return segments
+}
+
+// splitSegments splits the given string into segments of at most maxLength
+// bytes.
+func splitSegments(s string, maxLength int) []string {
+	var segments []string
+
+	for len(s) > maxLength {
+		segments = append(segments, s[:maxLength])
+		s = s[maxLength:]
+	}
+
+	return append(segments, s)
+}
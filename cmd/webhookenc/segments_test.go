@@ -0,0 +1,224 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+const testWorkflowURL string = "https://example.environment.api.powerplatform.com:443/powerautomate/automations/direct/workflows/ed3386c459104b11bd4e891c76e5e2a1/triggers/manual/paths/invoke?api-version=1&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=vqF0En-Z0ucuRTM01o2GuhMH3hKKkN2bOmlM31zaA"
+
+func TestSegmentVarNames(t *testing.T) {
+	tests := map[string]struct {
+		count    int
+		expected []string
+	}{
+		"none": {
+			count:    0,
+			expected: []string{},
+		},
+		"single": {
+			count:    1,
+			expected: []string{"_powerautomateworkflowurl_part1of1"},
+		},
+		"multiple": {
+			count: 3,
+			expected: []string{
+				"_powerautomateworkflowurl_part1of3",
+				"_powerautomateworkflowurl_part2of3",
+				"_powerautomateworkflowurl_part3of3",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := segmentVarNames(tt.count); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLParts(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected []string
+	}{
+		"workflow URL": {
+			input: testWorkflowURL,
+			expected: []string{
+				"https://example.environment.api.powerplatform.com:443",
+				"/powerautomate/automations/direct/workflows/ed3386c459104b11bd4e891c76e5e2a1/triggers/manual/paths/invoke?",
+				"api-version=1&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=vqF0En-Z0ucuRTM01o2GuhMH3hKKkN2bOmlM31zaA",
+			},
+		},
+		"no query": {
+			input:    "https://example.com/hook",
+			expected: []string{"https://example.com", "/hook"},
+		},
+		"query without path": {
+			input:    "https://example.com?sig=abc",
+			expected: []string{"https://example.com", "?sig=abc"},
+		},
+		"host only": {
+			input:    "https://example.com",
+			expected: []string{"https://example.com"},
+		},
+		"empty query": {
+			input:    "https://example.com/hook?",
+			expected: []string{"https://example.com", "/hook?"},
+		},
+		"not a URL": {
+			input:    "example",
+			expected: []string{"example"},
+		},
+		"empty": {
+			input:    "",
+			expected: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := urlParts(tt.input)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("got %q; expected %q", got, tt.expected)
+			}
+
+			if joined := strings.Join(got, ""); joined != tt.input {
+				t.Errorf("got %q; expected parts to combine to %q", joined, tt.input)
+			}
+		})
+	}
+}
+
+func TestSplitSegments(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		maxLength int
+		expected  []string
+	}{
+		"shorter than maximum": {
+			input:     "abc",
+			maxLength: 4,
+			expected:  []string{"abc"},
+		},
+		"exactly maximum": {
+			input:     "abcd",
+			maxLength: 4,
+			expected:  []string{"abcd"},
+		},
+		"one more than maximum": {
+			input:     "abcde",
+			maxLength: 4,
+			expected:  []string{"abcd", "e"},
+		},
+		"multiple of maximum": {
+			input:     "abcdefgh",
+			maxLength: 4,
+			expected:  []string{"abcd", "efgh"},
+		},
+		"empty": {
+			input:     "",
+			maxLength: 4,
+			expected:  []string{""},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := splitSegments(tt.input, tt.maxLength); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSplitURLMaxSegmentLength asserts that every encoded segment fits
+// within the maximum segment length, including lengths which are not a
+// multiple of the base64 block size, and that the segments decode back to
+// the original URL.
+func TestSplitURLMaxSegmentLength(t *testing.T) {
+	urls := map[string]string{
+		"workflow URL":  testWorkflowURL,
+		"connector URL": "https://example.webhook.office.com/webhookb2/a5c8f3e2-1234-4cde-9f00-abcdef012345@0a1b2c3d-4e5f-6789-abcd-ef0123456789/IncomingWebhook/0123456789abcdef0123456789abcdef/fedcba98-7654-3210-fedc-ba9876543210",
+	}
+
+	for name, rawURL := range urls {
+		t.Run(name, func(t *testing.T) {
+			for maxLength := minSegmentLength; maxLength <= len(rawURL)*2; maxLength++ {
+				rawSegments := splitURL(rawURL, maxLength)
+
+				if joined := strings.Join(rawSegments, ""); joined != rawURL {
+					t.Fatalf("max length %d: got %q; expected %q", maxLength, joined, rawURL)
+				}
+
+				encodedSegments := make([]string, 0, len(rawSegments))
+				for i, rawSegment := range rawSegments {
+					encoded := webhookurl.EncodeToBase64String([]byte(rawSegment))
+					if len(encoded) > maxLength {
+						t.Fatalf(
+							"max length %d: got %d characters for segment %d; expected at most %d",
+							maxLength, len(encoded), i+1, maxLength,
+						)
+					}
+					encodedSegments = append(encodedSegments, encoded)
+				}
+
+				decoded, err := webhookurl.DecodeBase64(strings.Join(encodedSegments, ","))
+				if err != nil {
+					t.Fatalf("max length %d: got %v; expected no error", maxLength, err)
+				}
+
+				if string(decoded) != rawURL {
+					t.Fatalf("max length %d: got %q; expected %q", maxLength, decoded, rawURL)
+				}
+			}
+		})
+	}
+}
+
+// TestSplitEncryptedMaxSegmentLength asserts that every segment of an
+// encrypted webhook URL fits within the maximum segment length and that the
+// segments decrypt back to the original URL.
+func TestSplitEncryptedMaxSegmentLength(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, webhookurl.KeySize)
+
+	encrypted, err := webhookurl.Encrypt(testWorkflowURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	for maxLength := minSegmentLength; maxLength <= len(encrypted)+1; maxLength++ {
+		segments := splitSegments(encrypted, maxLength)
+
+		for i, segment := range segments {
+			if len(segment) > maxLength {
+				t.Fatalf(
+					"max length %d: got %d characters for segment %d; expected at most %d",
+					maxLength, len(segment), i+1, maxLength,
+				)
+			}
+		}
+
+		decrypted, err := webhookurl.Decrypt(strings.Join(segments, ","), key)
+		if err != nil {
+			t.Fatalf("max length %d: got %v; expected no error", maxLength, err)
+		}
+
+		if decrypted != testWorkflowURL {
+			t.Fatalf("max length %d: got %q; expected %q", maxLength, decrypted, testWorkflowURL)
+		}
+	}
+}
@@ -13,4 +13,8 @@ go 1.23.0
 //
 // replace github.com/atc0005/go-teams-notify/v2 => ../go-teams-notify
 
-require github.com/atc0005/go-teams-notify/v2 v2.14.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/atc0005/go-teams-notify/v2 v2.14.0
+	go.yaml.in/yaml/v3 v3.0.5
+)
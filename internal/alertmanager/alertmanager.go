@@ -0,0 +1,194 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoAlerts indicates that a notification does not contain any alerts.
+var ErrNoAlerts = errors.New("notification does not contain any alerts")
+
+// Supported alert and notification status values.
+const (
+	StatusFiring   string = "firing"
+	StatusResolved string = "resolved"
+)
+
+// supportedVersion is the webhook payload version supported by this package.
+const supportedVersion string = "4"
+
+// KV is a set of key/value string pairs used for labels and annotations.
+type KV map[string]string
+
+// Names returns the sorted list of names in the set.
+func (kv KV) Names() []string {
+	names := make([]string, 0, len(kv))
+	for name := range kv {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Without returns the pairs in the set whose names are not present in the
+// given set (e.g., the labels of an alert not common to every alert).
+func (kv KV) Without(other KV) KV {
+	remaining := make(KV, len(kv))
+	for name, value := range kv {
+		if _, found := other[name]; !found {
+			remaining[name] = value
+		}
+	}
+
+	return remaining
+}
+
+// Alert is a single alert within an Alertmanager notification.
+type Alert struct {
+	Status       string    `json:"status"`
+	Labels       KV        `json:"labels"`
+	Annotations  KV        `json:"annotations"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt"`
+	GeneratorURL string    `json:"generatorURL"`
+	Fingerprint  string    `json:"fingerprint"`
+}
+
+// Notification is the payload of an Alertmanager webhook notification.
+//
+// https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type Notification struct {
+	Version           string  `json:"version"`
+	GroupKey          string  `json:"groupKey"`
+	TruncatedAlerts   int     `json:"truncatedAlerts"`
+	Status            string  `json:"status"`
+	Receiver          string  `json:"receiver"`
+	GroupLabels       KV      `json:"groupLabels"`
+	CommonLabels      KV      `json:"commonLabels"`
+	CommonAnnotations KV      `json:"commonAnnotations"`
+	ExternalURL       string  `json:"externalURL"`
+	Alerts            []Alert `json:"alerts"`
+}
+
+// Parse decodes the given Alertmanager webhook payload. An error is returned
+// if the payload is invalid, uses an unsupported version or does not
+// contain any alerts.
+func Parse(data []byte) (Notification, error) {
+	var n Notification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return Notification{}, fmt.Errorf("failed to parse Alertmanager notification: %w", err)
+	}
+
+	if n.Version != "" && n.Version != supportedVersion {
+		return Notification{}, fmt.Errorf(
+			"unsupported Alertmanager notification version %q; expected %q",
+			n.Version,
+			supportedVersion,
+		)
+	}
+
+	if len(n.Alerts) == 0 {
+		return Notification{}, ErrNoAlerts
+	}
+
+	return n, nil
+}
+
+// Firing returns the alerts which are currently firing.
+func (n Notification) Firing() []Alert {
+	return n.filter(StatusFiring)
+}
+
+// Resolved returns the alerts which have been resolved.
+func (n Notification) Resolved() []Alert {
+	return n.filter(StatusResolved)
+}
+
+// filter returns the alerts with the specified status.
+func (n Notification) filter(status string) []Alert {
+	alerts := make([]Alert, 0, len(n.Alerts))
+	for _, alert := range n.Alerts {
+		if alert.Status == status {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts
+}
+
+// Title returns a title for the notification emulating the default
+// Alertmanager notification title (e.g., "[FIRING:2] HighLatency (api)").
+func (n Notification) Title() string {
+	status := strings.ToUpper(n.Status)
+	if n.Status == StatusFiring {
+		status = fmt.Sprintf("%s:%d", status, len(n.Firing()))
+	}
+
+	var values []string
+	for _, name := range n.GroupLabels.Names() {
+		if name != "alertname" {
+			values = append(values, n.GroupLabels[name])
+		}
+	}
+
+	title := fmt.Sprintf("[%s] %s", status, n.CommonLabels["alertname"])
+	if len(values) > 0 {
+		title = fmt.Sprintf("%s (%s)", title, strings.Join(values, " "))
+	}
+
+	return strings.TrimSpace(title)
+}
+
+// UniqueLabels returns the labels for the given alert which are not common
+// to every alert in the notification.
+func (n Notification) UniqueLabels(alert Alert) KV {
+	return alert.Labels.Without(n.CommonLabels)
+}
+
+// SilenceURL returns a URL which opens the Alertmanager UI form for creating
+// a new silence matching the labels of the given alert. An empty string is
+// returned if the Alertmanager external URL is unknown.
+func (n Notification) SilenceURL(alert Alert) string {
+	if n.ExternalURL == "" {
+		return ""
+	}
+
+	matchers := make([]string, 0, len(alert.Labels))
+	for _, name := range alert.Labels.Names() {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, alert.Labels[name]))
+	}
+
+	filter := "{" + strings.Join(matchers, ",") + "}"
+
+	return strings.TrimSuffix(n.ExternalURL, "/") +
+		"/#/silences/new?filter=" + url.QueryEscape(filter)
+}
+
+// Name returns the name of the alert.
+func (a Alert) Name() string {
+	return a.Labels["alertname"]
+}
+
+// Summary returns a short description of the alert using the summary or
+// description annotations (if present).
+func (a Alert) Summary() string {
+	switch {
+	case a.Annotations["summary"] != "":
+		return a.Annotations["summary"]
+	default:
+		return a.Annotations["description"]
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package alertmanager
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testNotification string = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighLatency\"}",
+	"status": "firing",
+	"receiver": "teams",
+	"groupLabels": {"alertname": "HighLatency", "service": "api"},
+	"commonLabels": {"alertname": "HighLatency", "service": "api", "severity": "warning"},
+	"commonAnnotations": {"summary": "Request latency is high"},
+	"externalURL": "https://alertmanager.example.com/",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "service": "api", "severity": "warning", "instance": "web01:9090"},
+			"annotations": {"summary": "Latency on web01", "description": "p99 above 1s"},
+			"startsAt": "2026-10-17T12:00:00Z",
+			"generatorURL": "https://prometheus.example.com/graph?g0.expr=latency"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "HighLatency", "service": "api", "severity": "warning", "instance": "web02:9090"},
+			"annotations": {"description": "p99 above 1s"},
+			"startsAt": "2026-10-17T11:00:00Z",
+			"endsAt": "2026-10-17T11:30:00Z"
+		},
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "service": "api", "severity": "warning", "instance": "web03:9090"}
+		}
+	]
+}`
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		payload        string
+		expectedAlerts int
+		expectedErr    error
+		expectErr      bool
+	}{
+		"valid": {
+			payload:        testNotification,
+			expectedAlerts: 3,
+		},
+		"version omitted": {
+			payload:        `{"status": "firing", "alerts": [{"status": "firing"}]}`,
+			expectedAlerts: 1,
+		},
+		"unsupported version": {
+			payload:   `{"version": "3", "status": "firing", "alerts": [{"status": "firing"}]}`,
+			expectErr: true,
+		},
+		"no alerts": {
+			payload:     `{"version": "4", "status": "firing", "alerts": []}`,
+			expectErr:   true,
+			expectedErr: ErrNoAlerts,
+		},
+		"invalid JSON": {
+			payload:   `{"version": "4", "alerts": `,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse([]byte(tt.payload))
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got %+v; expected error", n)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case len(n.Alerts) != tt.expectedAlerts:
+				t.Errorf("got %d alerts; expected %d", len(n.Alerts), tt.expectedAlerts)
+			}
+		})
+	}
+}
+
+func TestFiringResolved(t *testing.T) {
+	n, err := Parse([]byte(testNotification))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	instances := func(alerts []Alert) []string {
+		var values []string
+		for _, alert := range alerts {
+			values = append(values, alert.Labels["instance"])
+		}
+
+		return values
+	}
+
+	if got, expected := instances(n.Firing()), []string{"web01:9090", "web03:9090"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got firing alerts %v; expected %v", got, expected)
+	}
+
+	if got, expected := instances(n.Resolved()), []string{"web02:9090"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got resolved alerts %v; expected %v", got, expected)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := map[string]struct {
+		notification Notification
+		expected     string
+	}{
+		"firing with group labels": {
+			notification: Notification{
+				Status:       StatusFiring,
+				GroupLabels:  KV{"alertname": "HighLatency", "service": "api", "env": "prod"},
+				CommonLabels: KV{"alertname": "HighLatency"},
+				Alerts: []Alert{
+					{Status: StatusFiring},
+					{Status: StatusResolved},
+					{Status: StatusFiring},
+				},
+			},
+			expected: "[FIRING:2] HighLatency (prod api)",
+		},
+		"resolved": {
+			notification: Notification{
+				Status:       StatusResolved,
+				GroupLabels:  KV{"alertname": "HighLatency"},
+				CommonLabels: KV{"alertname": "HighLatency"},
+				Alerts:       []Alert{{Status: StatusResolved}},
+			},
+			expected: "[RESOLVED] HighLatency",
+		},
+		"firing without group labels": {
+			notification: Notification{
+				Status:       StatusFiring,
+				CommonLabels: KV{"alertname": "DiskFull"},
+				Alerts:       []Alert{{Status: StatusFiring}},
+			},
+			expected: "[FIRING:1] DiskFull",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.notification.Title(); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUniqueLabels(t *testing.T) {
+	n := Notification{
+		CommonLabels: KV{"alertname": "HighLatency", "service": "api", "region": ""},
+	}
+
+	alert := Alert{
+		Labels: KV{
+			"alertname": "HighLatency",
+			"service":   "api",
+			"region":    "",
+			"instance":  "web01:9090",
+			"pod":       "api-7d9f",
+		},
+	}
+
+	expected := KV{"instance": "web01:9090", "pod": "api-7d9f"}
+	if got := n.UniqueLabels(alert); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; expected %v", got, expected)
+	}
+
+	if got := n.UniqueLabels(Alert{}); len(got) != 0 {
+		t.Errorf("got %v; expected no labels", got)
+	}
+}
+
+func TestSilenceURL(t *testing.T) {
+	alert := Alert{
+		Labels: KV{
+			"alertname": "HighLatency",
+			"instance":  "web01:9090",
+			"path":      `/api?q="a b"&x=1`,
+		},
+	}
+
+	tests := map[string]struct {
+		externalURL string
+		expected    string
+	}{
+		"trailing slash": {
+			externalURL: "https://alertmanager.example.com/",
+			expected:    "https://alertmanager.example.com/#/silences/new?filter=",
+		},
+		"no trailing slash": {
+			externalURL: "https://alertmanager.example.com",
+			expected:    "https://alertmanager.example.com/#/silences/new?filter=",
+		},
+		"unknown external URL": {
+			externalURL: "",
+		},
+	}
+
+	expectedFilter := `{alertname="HighLatency",instance="web01:9090",path="/api?q=\"a b\"&x=1"}`
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := Notification{ExternalURL: tt.externalURL}
+			got := n.SilenceURL(alert)
+
+			if tt.expected == "" {
+				if got != "" {
+					t.Errorf("got %q; expected empty URL", got)
+				}
+
+				return
+			}
+
+			expected := tt.expected + url.QueryEscape(expectedFilter)
+			if got != expected {
+				t.Fatalf("got %q; expected %q", got, expected)
+			}
+
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("got %v; expected valid URL", err)
+			}
+
+			// The filter is placed in the URL fragment as the Alertmanager UI
+			// is a single page application.
+			_, rawQuery, _ := strings.Cut(u.EscapedFragment(), "?")
+			query, err := url.ParseQuery(rawQuery)
+			if err != nil {
+				t.Fatalf("got %v; expected valid query", err)
+			}
+
+			if filter := query.Get("filter"); filter != expectedFilter {
+				t.Errorf("got filter %q; expected %q", filter, expectedFilter)
+			}
+		})
+	}
+}
+
+func TestAlertNameSummary(t *testing.T) {
+	tests := map[string]struct {
+		alert           Alert
+		expectedName    string
+		expectedSummary string
+	}{
+		"summary": {
+			alert: Alert{
+				Labels:      KV{"alertname": "HighLatency"},
+				Annotations: KV{"summary": "Latency on web01", "description": "p99 above 1s"},
+			},
+			expectedName:    "HighLatency",
+			expectedSummary: "Latency on web01",
+		},
+		"description": {
+			alert: Alert{
+				Labels:      KV{"alertname": "HighLatency"},
+				Annotations: KV{"description": "p99 above 1s"},
+			},
+			expectedName:    "HighLatency",
+			expectedSummary: "p99 above 1s",
+		},
+		"no labels or annotations": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.alert.Name(); got != tt.expectedName {
+				t.Errorf("got name %q; expected %q", got, tt.expectedName)
+			}
+
+			if got := tt.alert.Summary(); got != tt.expectedSummary {
+				t.Errorf("got summary %q; expected %q", got, tt.expectedSummary)
+			}
+		})
+	}
+}
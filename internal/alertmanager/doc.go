@@ -0,0 +1,10 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package alertmanager supports processing Prometheus Alertmanager webhook
+// notifications.
+package alertmanager
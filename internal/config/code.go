@@ -0,0 +1,120 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// hasCodeSource indicates whether a code file or standard input was
+// specified as the source of a code block.
+func (c Config) hasCodeSource() bool {
+	return c.CodeFile != "" || c.CodeFromStdin
+}
+
+// codeReadsStdin indicates whether the code block is read from standard
+// input.
+func (c Config) codeReadsStdin() bool {
+	return c.CodeFromStdin || c.CodeFile == stdinFileName
+}
+
+// messageReadsStdin indicates whether the message, payload, notification or
+// template data is read from standard input.
+func (c Config) messageReadsStdin() bool {
+	switch {
+	case c.MessageFromStdin, c.MessageFile == stdinFileName:
+		return true
+	case c.InputFormat != "" && c.MessageFile == "":
+		return true
+	case c.PayloadFile == stdinFileName, c.TemplateData == stdinFileName:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleCodeInput populates the Code field using the user-specified code
+// file or standard input. Trailing newlines are removed and content
+// consisting only of whitespace is treated as empty. An error is returned if
+// more than one source was specified, if standard input is also used for
+// other input or if reading the content fails.
+func (c *Config) handleCodeInput() error {
+	switch {
+	case !c.hasCodeSource():
+		return nil
+
+	case c.CodeFile != "" && c.CodeFromStdin:
+		return fmt.Errorf(
+			"%w: only one of code-file or code-stdin flags may be used",
+			ErrConflictingMessageSources,
+		)
+
+	case c.codeReadsStdin() && c.messageReadsStdin():
+		return fmt.Errorf(
+			"%w: standard input may not be used for both the code block and the message, payload, notification or template data",
+			ErrConflictingMessageSources,
+		)
+	}
+
+	var code string
+	var err error
+
+	switch {
+	case c.codeReadsStdin():
+		code, err = readMessage(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read code block from standard input: %w", err)
+		}
+
+	default:
+		data, readErr := readInputFile(c.CodeFile)
+		if readErr != nil {
+			return fmt.Errorf("failed to read code file: %w", readErr)
+		}
+
+		code, err = readMessage(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to read code file %s: %w", c.CodeFile, err)
+		}
+	}
+
+	c.Code = strings.ToValidUTF8(code, "�")
+
+	return nil
+}
+
+// validateCode asserts that the settings used to attach a code block are
+// valid. Code blocks are only supported for messages generated from message
+// text.
+func (c Config) validateCode() error {
+	if !c.hasCodeSource() {
+		return nil
+	}
+
+	switch {
+	case c.Command != "":
+		return fmt.Errorf(
+			"unsupported: You cannot use code-file or code-stdin options with the %s command",
+			c.Command,
+		)
+
+	case len(c.Payload) > 0:
+		return fmt.Errorf("unsupported: You cannot use code-file or code-stdin options with a payload file")
+
+	case c.InputFormat != "":
+		return fmt.Errorf("unsupported: You cannot use code-file or code-stdin options with an input format")
+
+	case strings.TrimSpace(c.CodeLanguage) == "":
+		return fmt.Errorf("code block language not specified")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"slices"
+)
+
+// Supported subcommands. If a subcommand is not specified a single message
+// is submitted.
+const (
+	// CommandServe runs a long-lived HTTP server which relays alerts
+	// submitted by clients to Microsoft Teams.
+	CommandServe string = "serve"
+
+	// CommandExec runs the specified command and submits a message
+	// describing the result.
+	CommandExec string = "exec"
+
+	// CommandFlush delivers messages written to the spool directory after
+	// delivery failed.
+	CommandFlush string = "flush"
+)
+
+// supportedCommands returns the list of supported subcommands.
+func supportedCommands() []string {
+	return []string{
+		CommandServe,
+		CommandExec,
+		CommandFlush,
+	}
+}
+
+// splitCommand returns the subcommand specified as the first of the given
+// command-line arguments (if any) along with the remaining arguments.
+func splitCommand(args []string) (string, []string) {
+	if len(args) > 0 && slices.Contains(supportedCommands(), args[0]) {
+		return args[0], args[1:]
+	}
+
+	return "", args
+}
@@ -14,33 +14,71 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
-	"github.com/atc0005/send2teams/internal/webhookurl"
 )
 
 const (
 	versionFlagHelp                     = "Whether to display application version and then immediately exit application."
 	verboseOutputFlagHelp               = "Whether detailed output should be shown after message submission success or failure."
 	silentOutputFlagHelp                = "Whether ANY output should be shown after message submission success or failure."
+	showSecretsFlagHelp                 = "Whether webhook URL secrets (e.g., the sig signature and workflow ID of Power Automate workflow URLs) should be shown in log, verbose and error output. Secrets are masked by default; use only when debugging."
 	disableWebhookURLValidationFlagHelp = "Whether webhook URL validation should be disabled. Useful when submitting generated JSON payloads to a service like \"https://httpbin.org/\"."
 	disableBrandingTrailerFlagHelp      = "Whether the branding trailer should be omitted from all messages generated by this application."
 	ignoreInvalidResponseFlagHelp       = "Whether an invalid response from remote endpoint should be ignored. This is expected if submitting a message to a non-standard webhook URL."
 	convertEOLFlagHelp                  = "Whether messages with Windows, Mac and Linux newlines are updated to use break statements before message submission."
 	teamNameFlagHelp                    = "The name of the Team containing our target channel. Used in log messages. If not specified, defaults to \"unspecified\"."
 	channelNameFlagHelp                 = "The target channel where we will send a message. Used in log messages. If not specified, defaults to \"unspecified\"."
-	webhookURLFlagHelp                  = "The target webhook URL used for delivering Microsoft Teams notifications. May optionally be base64 encoded and will be transparently decoded before use."
+	webhookURLFlagHelp                  = "The target webhook URL used for delivering Microsoft Teams notifications. May optionally be base64 encoded and will be transparently decoded before use. May be repeated to deliver the same message to multiple webhook URLs."
+	urlFileFlagHelp                     = "The path to a file containing the target webhook URL (one per line if multiple). The file must not be readable by group or other users. Avoids exposing the webhook URL in process listings and shell history. May not be used with the url flag."
+	allowInsecureURLFileFlagHelp        = "Whether a webhook URL file or webhook URL key file readable by group or other users should be accepted."
+	keyFileFlagHelp                     = "The path to a file containing the key used to decrypt encrypted webhook URLs generated by webhookenc. The file must not be readable by group or other users."
 	targetURLFlagHelp                   = "The target URL and label (specified as comma separated pair) usually visible as a button towards the bottom of the Microsoft Teams message."
+	factFlagHelp                        = "The title and value (specified as comma separated pair) for a fact displayed in tabular form below the message. May be repeated to display multiple facts."
 	userMentionFlagHelp                 = "The DisplayName and ID of the recipient (specified as comma separated pair) for a user mention."
-	themeColorFlagHelp                  = "NOOP; this setting is no longer used. Values specified for this flag are ignored."
+	themeColorFlagHelp                  = "Alias for the severity flag. Severity level names and common color names (e.g., green, yellow, red) are mapped to the equivalent severity level. Other values (e.g., hex codes) are ignored."
+	severityFlagHelp                    = "The (optional) severity level of the message used to style the message title and body. Supported values are info, ok, warning, critical and unknown."
 	titleFlagHelp                       = "The title for the message to submit."
 	messageFlagHelp                     = "The message to submit. This message may be provided in Markdown format."
+	messageFileFlagHelp                 = "The path to a file containing the message to submit. Use \"-\" to read the message from standard input. This message may be provided in Markdown format."
+	codeFileFlagHelp                    = "The path to a file containing code or log output displayed as a code block below the message. Use \"-\" to read the content from standard input."
+	codeFromStdinFlagHelp               = "Whether code or log output displayed as a code block below the message should be read from standard input."
+	codeLanguageFlagHelp                = "The language used to highlight the code block (e.g., Bash, Go, JSON, PowerShell, Python, SQL, XML or PlainText)."
+	tableFileFlagHelp                   = "The path to a CSV or TSV file containing tabular data displayed as a table below the message. Use \"-\" to read the data from standard input."
+	tableFormatFlagHelp                 = "The format of the tabular data. Supported values are csv and tsv. If not specified, the format is detected from the file extension or the first line of data."
+	tableHeaderFlagHelp                 = "Whether the first row of the tabular data is displayed as a header row."
+	tableGridFlagHelp                   = "Whether grid lines are displayed for the table."
+	tableMaxColumnsFlagHelp             = "The maximum number of columns displayed in the table. Additional columns are omitted."
+	tableMaxRowsFlagHelp                = "The maximum number of rows (excluding the header row) displayed in the table. Additional rows are omitted."
+	payloadFileFlagHelp                 = "The path to a file containing a JSON Adaptive Card or complete Microsoft Teams message to submit as-is. Use \"-\" to read the payload from standard input. The branding trailer is appended unless disabled."
+	templateFlagHelp                    = "A Go text/template used to render the message to submit. The message title is rendered from a \"title\" template if defined (e.g., {{define \"title\"}}...{{end}})."
+	templateFileFlagHelp                = "The path to a file containing a Go text/template used to render the message to submit. The message title is rendered from a \"title\" template if defined."
+	templateVarFlagHelp                 = "The name and value (specified as key=value pair) of a variable made available to the message template as {{.Vars.key}}. May be repeated to specify multiple variables."
+	templateDataFlagHelp                = "The path to a file containing JSON data made available to the message template as {{.Data}}. Use \"-\" to read the data from standard input."
+	inputFormatFlagHelp                 = "The format of a notification read from standard input (or the message file) used to generate the message. Supported values are alertmanager and grafana."
+	messageFromStdinFlagHelp            = "Whether the message to submit should be read from standard input. This message may be provided in Markdown format."
 	senderFlagHelp                      = "The (optional) sending application name or generator of the message this app will attempt to deliver."
 	retriesFlagHelp                     = "The number of attempts that this application will make to deliver messages before giving up."
 	retriesDelayFlagHelp                = "The number of seconds that this application will wait before making another delivery attempt."
+	configFileFlagHelp                  = "The path to an (optional) TOML, YAML or JSON configuration file (format determined by the .toml, .yaml, .yml or .json file extension) providing default settings and named destinations. Settings specified via flag take precedence over settings from the configuration file."
+	failurePolicyFlagHelp               = "The policy used to determine whether delivery to multiple webhook URLs is considered a failure. Use \"any\" to fail if delivery to any webhook URL fails or \"all\" to fail only if delivery to all webhook URLs fails."
+	oversizeFlagHelp                    = "The policy used when the generated message exceeds the Microsoft Teams message size limit (approximately 28 KB). Use \"fail\" to report an error, \"truncate\" to truncate the message body or \"split\" to split the message body across multiple messages."
+	dryRunFlagHelp                      = "Whether the generated message payload should be written to standard output instead of being submitted. A webhook URL is not required when this option is used."
+	listenFlagHelp                      = "The address (host:port) on which the serve command listens for alerts submitted by clients."
+	nagiosModeFlagHelp                  = "Whether the message title, text, facts and styling should be generated from Nagios environment macros (e.g., NAGIOS_HOSTNAME, NAGIOS_SERVICESTATE). Requires that the Nagios enable_environment_macros setting is enabled."
+	icinga2ModeFlagHelp                 = "Whether the message title, text, facts, styling and links should be generated from the environment variables set by an Icinga 2 NotificationCommand (e.g., NOTIFICATIONTYPE, HOSTNAME, SERVICESTATE)."
+	zabbixModeFlagHelp                  = "Whether the message title, text, facts, styling and webhook URL should be generated from Zabbix media type script parameters ({ALERT.SENDTO} {ALERT.SUBJECT} {ALERT.MESSAGE} followed by optional name=value pairs, or a single JSON parameter set) provided after all flags."
+	notifyOnFlagHelp                    = "When the exec command submits a message for the result of the wrapped command. Supported values are always, failure (only when the command fails) and change (only when the result differs from the previous run)."
+	stateFileFlagHelp                   = "The path to the file used by the exec command to record the result of the previous run for the change notify-on policy. If not specified, a file specific to the command in the user cache directory is used."
+	icingaweb2URLFlagHelp               = "The (optional) base URL of Icinga Web 2 used to link to the host or service in Icinga 2 mode. If not specified, the ICINGAWEB2URL environment variable is used."
+	spoolDirFlagHelp                    = "The path to an (optional) directory where messages are written if delivery fails. Messages in the spool directory are delivered by the flush command."
+	spoolMaxAgeFlagHelp                 = "The maximum amount of time (e.g., 30m, 24h) after the first failed delivery that the flush command attempts to deliver a spooled message before moving it to the dead-letter directory."
+	spoolMaxAttemptsFlagHelp            = "The maximum number of delivery attempts made for a spooled message before the flush command moves it to the dead-letter directory."
+	destinationFlagHelp                 = "The name of a destination defined in the configuration file. Settings for the destination (e.g., webhook URL, team, channel) are used unless overridden via flag."
 )
 
 // shorthandFlagSuffix is appended to short flag help text to emphasize that
@@ -50,21 +88,91 @@ const shorthandFlagSuffix = " (shorthand)"
 // Default flag settings if not overridden by user input
 const (
 	defaultMessageThemeColor           string = "NotUsed"
+	defaultSeverity                    string = ""
 	defaultSilentOutput                bool   = false
 	defaultVerboseOutput               bool   = false
+	defaultShowSecrets                 bool   = false
 	defaultConvertEOL                  bool   = false
 	defaultDisableWebhookURLValidation bool   = false
 	defaultDisableBrandingTrailer      bool   = false
 	defaultIgnoreInvalidResponse       bool   = false
 	defaultTeamName                    string = "unspecified"
 	defaultChannelName                 string = "unspecified"
-	defaultWebhookURL                  string = ""
 	defaultMessageTitle                string = ""
 	defaultMessageText                 string = ""
+	defaultMessageFile                 string = ""
+	defaultMessageFromStdin            bool   = false
+	defaultPayloadFile                 string = ""
+	defaultURLFile                     string = ""
+	defaultAllowInsecureURLFile        bool   = false
+	defaultKeyFile                     string = ""
+	defaultTableFile                   string = ""
+	defaultTableFormat                 string = ""
+	defaultTableHeader                 bool   = true
+	defaultTableGrid                   bool   = true
+	defaultTableMaxColumns             int    = 8
+	defaultTableMaxRows                int    = 50
+	defaultCodeFile                    string = ""
+	defaultCodeFromStdin               bool   = false
+	defaultCodeLanguage                string = "PlainText"
+	defaultTemplate                    string = ""
+	defaultInputFormat                 string = ""
+	defaultTemplateFile                string = ""
+	defaultTemplateData                string = ""
 	defaultSender                      string = ""
 	defaultDisplayVersionAndExit       bool   = false
 	defaultRetries                     int    = 2
 	defaultRetriesDelay                int    = 2
+	defaultConfigFile                  string = ""
+	defaultDestination                 string = ""
+	defaultFailurePolicy               string = FailurePolicyAny
+	defaultNagiosMode                  bool   = false
+	defaultIcinga2Mode                 bool   = false
+	defaultIcingaweb2URL               string = ""
+	defaultZabbixMode                  bool   = false
+	defaultDryRun                      bool   = false
+	defaultOversize                    string = OversizeFail
+	defaultListenAddress               string = "localhost:8080"
+	defaultNotifyOn                    string = NotifyOnAlways
+	defaultStateFile                   string = ""
+	defaultSpoolDir                    string = ""
+	defaultSpoolMaxAttempts            int    = 10
+)
+
+// defaultSpoolMaxAge is the default maximum amount of time after the first
+// failed delivery that delivery of a spooled message is attempted.
+const defaultSpoolMaxAge time.Duration = 24 * time.Hour
+
+// Supported failure policies used to determine the overall result of
+// delivering a message to multiple webhook URLs.
+const (
+	// FailurePolicyAny indicates that failure to deliver a message to any
+	// webhook URL is treated as an overall failure.
+	FailurePolicyAny string = "any"
+
+	// FailurePolicyAll indicates that only failure to deliver a message to
+	// all webhook URLs is treated as an overall failure.
+	FailurePolicyAll string = "all"
+)
+
+// Supported input formats for notifications generated by other applications.
+const (
+	// InputFormatAlertmanager is the Prometheus Alertmanager webhook
+	// notification format.
+	InputFormatAlertmanager string = "alertmanager"
+
+	// InputFormatGrafana is the Grafana alerting webhook notification
+	// format.
+	InputFormatGrafana string = "grafana"
+)
+
+// Supported message severity levels.
+const (
+	SeverityInfo     string = "info"
+	SeverityOK       string = "ok"
+	SeverityWarning  string = "warning"
+	SeverityCritical string = "critical"
+	SeverityUnknown  string = "unknown"
 )
 
 // Overridden via Makefile for release builds
@@ -138,6 +246,16 @@ type UserMention struct {
 	Name string
 }
 
+// Fact is a pair of title and value used to display key details in
+// tabular form within the generated Microsoft Teams message.
+type Fact struct {
+	// Title is the label for the fact.
+	Title string
+
+	// Value is the value of the fact.
+	Value string
+}
+
 // Config is a unified set of configuration values for this application. This
 // struct is configured via command-line flags provided by the user.
 type Config struct {
@@ -153,14 +271,33 @@ type Config struct {
 	// by this application only; the remote API does not receive this value.
 	Channel string
 
-	// webhookURL is the full URL used to submit messages to the Teams
-	// channel. This URL value may optionally be base64 encoded and will be
-	// transparently decoded before use.
-	webhookURL string
+	// webhookURLs is the collection of full URLs used to submit messages to
+	// Teams channels. Each URL value may optionally be base64 encoded and
+	// will be transparently decoded before use.
+	webhookURLs webhookURLsStringFlag
 
-	// ThemeColor is no longer used. Values specified for this flag are
-	// ignored. If/when the Adaptive Card format adds support for message
-	// theming (or border color) we can re-enable this setting.
+	// URLFile is the path to a file containing the target webhook URL (or
+	// URLs, one per line).
+	URLFile string
+
+	// AllowInsecureURLFile indicates whether a webhook URL file or webhook
+	// URL key file readable by group or other users is accepted.
+	AllowInsecureURLFile bool
+
+	// KeyFile is the path to a file containing the key used to decrypt
+	// encrypted webhook URLs.
+	KeyFile string
+
+	// webhookURLKey is the key used to decrypt encrypted webhook URLs.
+	webhookURLKey []byte
+
+	// FailurePolicy determines whether failure to deliver a message to any
+	// or all webhook URLs is treated as an overall failure.
+	FailurePolicy string
+
+	// ThemeColor is an alias for Severity retained for compatibility with
+	// existing commands. Severity level names and common color names are
+	// mapped to the equivalent severity level, other values are ignored.
 	ThemeColor string
 
 	// MessageTitle is the text shown on the top portion of the message "card"
@@ -171,6 +308,92 @@ type Config struct {
 	// the message that we will submit.
 	MessageText string
 
+	// MessageFile is the path to a file containing the message to submit. If
+	// set to "-" the message is read from standard input.
+	MessageFile string
+
+	// MessageFromStdin indicates whether the message to submit should be
+	// read from standard input.
+	MessageFromStdin bool
+
+	// CodeFile is the path to a file containing code or log output displayed
+	// as a code block below the message. If set to "-" the content is read
+	// from standard input.
+	CodeFile string
+
+	// CodeFromStdin indicates whether code or log output displayed as a code
+	// block below the message should be read from standard input.
+	CodeFromStdin bool
+
+	// CodeLanguage is the language used to highlight the code block.
+	CodeLanguage string
+
+	// Code is the code or log output read from the user-specified code file
+	// or standard input.
+	Code string
+
+	// TableFile is the path to a CSV or TSV file containing tabular data
+	// displayed as a table below the message. If set to "-" the data is
+	// read from standard input.
+	TableFile string
+
+	// TableFormat is the format (csv or tsv) of the tabular data. The format
+	// is detected if not specified.
+	TableFormat string
+
+	// TableHeader indicates whether the first row of the tabular data is
+	// displayed as a header row.
+	TableHeader bool
+
+	// TableGrid indicates whether grid lines are displayed for the table.
+	TableGrid bool
+
+	// TableMaxColumns is the maximum number of columns displayed in the
+	// table.
+	TableMaxColumns int
+
+	// TableMaxRows is the maximum number of rows (excluding the header row)
+	// displayed in the table.
+	TableMaxRows int
+
+	// Table is the tabular data read from the user-specified table file or
+	// standard input.
+	Table [][]string
+
+	// PayloadFile is the path to a file containing a JSON Adaptive Card or
+	// complete Microsoft Teams message. If set to "-" the payload is read
+	// from standard input.
+	PayloadFile string
+
+	// Payload is the JSON Adaptive Card or complete Microsoft Teams message
+	// read from the user-specified payload file.
+	Payload []byte
+
+	// InputFormat is the format of a notification generated by another
+	// application (e.g., Alertmanager) used to generate the message.
+	InputFormat string
+
+	// Input is the notification generated by another application read from
+	// standard input or the user-specified message file.
+	Input []byte
+
+	// Template is a Go text/template used to render the message title and
+	// text.
+	Template string
+
+	// TemplateFile is the path to a file containing a Go text/template used
+	// to render the message title and text.
+	TemplateFile string
+
+	// TemplateVars is the collection of user-specified variables made
+	// available to the message template.
+	TemplateVars templateVarsStringFlag
+
+	// TemplateData is the path to a file containing JSON data made available
+	// to the message template. If set to "-" the data is read from standard
+	// input.
+	TemplateData string
+
 	// Sender is an optional value provided to indicate what application was
 	// responsible for generating the message that this one will attempt to
 	// deliver.
@@ -189,6 +412,15 @@ type Config struct {
 	// Microsoft Teams message.
 	UserMentions userMentionsStringFlag
 
+	// Facts is the collection of title and value pairs that should be
+	// displayed in tabular form within the generated Microsoft Teams
+	// message.
+	Facts factsStringFlag
+
+	// Severity is the (optional) severity level of the message used to
+	// style the generated Microsoft Teams message.
+	Severity string
+
 	// Retries is the number of attempts that this application will make
 	// to deliver messages before giving up.
 	Retries int
@@ -217,6 +449,10 @@ type Config struct {
 	// failure.
 	SilentOutput bool
 
+	// ShowSecrets indicates whether webhook URL secrets should be shown in
+	// log, verbose and error output instead of being masked.
+	ShowSecrets bool
+
 	// Whether messages with Windows, Mac and Linux newlines are updated to
 	// use break statements before message submission.
 	ConvertEOL bool
@@ -224,12 +460,130 @@ type Config struct {
 	// ShowVersion is a flag indicating whether the user opted to display only
 	// the version string and then immediately exit the application
 	ShowVersion bool
+
+	// NagiosMode indicates whether the message title, text, facts and
+	// styling should be generated from Nagios environment macros.
+	NagiosMode bool
+
+	// Icinga2Mode indicates whether the message title, text, facts, styling
+	// and links should be generated from Icinga 2 environment variables.
+	Icinga2Mode bool
+
+	// Icingaweb2URL is the base URL of Icinga Web 2 used to link to the host
+	// or service in Icinga 2 mode.
+	Icingaweb2URL string
+
+	// ZabbixMode indicates whether the message title, text, facts, styling
+	// and webhook URL should be generated from Zabbix media type script
+	// parameters.
+	ZabbixMode bool
+
+	// Args is the list of positional command-line arguments remaining after
+	// flags are parsed.
+	Args []string
+
+	// NotifyOn determines when the exec command submits a message for the
+	// result of the wrapped command.
+	NotifyOn string
+
+	// StateFile is the path to the file used by the exec command to record
+	// the result of the previous run.
+	StateFile string
+
+	// SpoolDir is the path to the directory where messages are written if
+	// delivery fails.
+	SpoolDir string
+
+	// SpoolMaxAge is the maximum amount of time after the first failed
+	// delivery that delivery of a spooled message is attempted.
+	SpoolMaxAge time.Duration
+
+	// SpoolMaxAttempts is the maximum number of delivery attempts made for a
+	// spooled message.
+	SpoolMaxAttempts int
+
+	// DryRun indicates whether the generated message payload should be
+	// written to standard output instead of being submitted.
+	DryRun bool
+
+	// Oversize determines how a generated message exceeding the Microsoft
+	// Teams message size limit is handled.
+	Oversize string
+
+	// Command is the user-specified subcommand (e.g., serve). If not
+	// specified a single message is submitted.
+	Command string
+
+	// ListenAddress is the address (host:port) on which the serve command
+	// listens for alerts submitted by clients.
+	ListenAddress string
+
+	// ConfigFile is the path to an optional TOML, YAML or JSON
+	// configuration file providing default settings and named destinations.
+	ConfigFile string
+
+	// Destination is the name of a destination defined in the configuration
+	// file whose settings should be used.
+	Destination string
+
+	// destinations is the collection of configurations for each destination
+	// defined in the configuration file, indexed by destination name. This
+	// is only populated for the serve command.
+	destinations map[string]Config
+
+	// valueSources records the source (e.g., flag, environment variable,
+	// configuration file) of each setting, indexed by flag name. Settings
+	// not recorded here use default values.
+	valueSources map[string]valueSource
 }
 
+type webhookURLsStringFlag []string
+
 type targetURLsStringFlag []TargetURL
 
 type userMentionsStringFlag []UserMention
 
+type factsStringFlag []Fact
+
+// reset clears all previously specified webhook URLs.
+func (wus *webhookURLsStringFlag) reset() {
+	*wus = nil
+}
+
+// String returns a list of all user-specified webhook URLs.
+func (wus *webhookURLsStringFlag) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if wus == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%q", []string(*wus))
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present. Each flag invocation specifies a single webhook URL. Because
+// base64 encoded webhook URLs may be composed of comma separated segments,
+// values are not split on commas. An error is returned if an empty value is
+// specified.
+func (wus *webhookURLsStringFlag) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("empty webhook URL specified")
+	}
+
+	*wus = append(*wus, value)
+
+	return nil
+}
+
+// reset clears all previously specified target URLs.
+func (tus *targetURLsStringFlag) reset() {
+	*tus = nil
+}
+
 // String returns a list of all user-specified target URLs.
 func (tus *targetURLsStringFlag) String() string {
 
@@ -318,6 +672,11 @@ func (tus *targetURLsStringFlag) Set(value string) error {
 	return nil
 }
 
+// reset clears all previously specified user mentions.
+func (ums *userMentionsStringFlag) reset() {
+	*ums = nil
+}
+
 // String returns a list of all user-specified user mentions.
 func (ums *userMentionsStringFlag) String() string {
 
@@ -376,6 +735,73 @@ func (ums *userMentionsStringFlag) Set(value string) error {
 	return nil
 }
 
+// reset clears all previously specified facts.
+func (fs *factsStringFlag) reset() {
+	*fs = nil
+}
+
+// String returns a list of all user-specified facts.
+func (fs *factsStringFlag) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if fs == nil {
+		return ""
+	}
+
+	var output strings.Builder
+
+	for i, fact := range *fs {
+		fmt.Fprintf(&output, "[Title: %s, Value: %s]", fact.Title, fact.Value)
+
+		// separate the current entry from the next if more to process
+		if i+1 != len(*fs) {
+			fmt.Fprintf(&output, ", ")
+		}
+	}
+
+	return output.String()
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present. The value is split on the first comma in order to specify
+// the title and value for a fact; any additional commas are retained as part
+// of the fact value. An error is returned if either the title or the value
+// is empty.
+func (fs *factsStringFlag) Set(value string) error {
+
+	// split comma-separated string into title and value
+	items := strings.SplitN(value, ",", 2)
+
+	if len(items) != 2 {
+		return fmt.Errorf(
+			"received %d arguments for fact flag, expected 2",
+			len(items),
+		)
+	}
+
+	// prune any leading and trailing whitespace, drop any quotes which might
+	// cause issues later.
+	for index, item := range items {
+		items[index] = strings.TrimSpace(item)
+		items[index] = strings.ReplaceAll(items[index], "'", "")
+		items[index] = strings.ReplaceAll(items[index], "\"", "")
+	}
+
+	if items[0] == "" || items[1] == "" {
+		return fmt.Errorf("fact title and value must not be empty")
+	}
+
+	// add them to the collection
+	*fs = append(*fs, Fact{
+		Title: items[0],
+		Value: items[1],
+	})
+
+	return nil
+}
+
 // Branding is responsible for emitting application name, version and origin
 func Branding() {
 	_, _ = fmt.Fprintf(flag.CommandLine.Output(), "\n%s %s\n%s\n\n", myAppName, version, myAppURL)
@@ -414,96 +840,97 @@ func flagsUsage() func() {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage of \"%s\":\n",
 			myBinaryName,
 		)
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "  %s [%s] [flags]\n\n",
+			myBinaryName,
+			strings.Join(supportedCommands(), "|"),
+		)
 		flag.PrintDefaults()
 
 	}
 }
 
+// String provides a human-readable representation of the configuration
+// settings. Each setting provided via flag is annotated with the source of
+// its value (e.g., flag, env, config or default).
 func (c Config) String() string {
-	switch {
-	case webhookurl.IsBase64URL(c.webhookURL):
-		return fmt.Sprintf(
-			"Team=%q, "+
-				"Channel=%q, "+
-				"WebhookURL (raw)=%q, "+
-				"WebhookURL (decoded)=%q, "+
-				"ThemeColor=%q, "+
-				"MessageTitle=%q, "+
-				"MessageText=%q, "+
-				"Sender=%q, "+
-				"TargetURLs=%q, "+
-				"Retries=%q, "+
-				"RetriesDelay=%q, "+
-				"AppTimeout=%q, "+
-				"DisableWebhookURLValidation=%t, "+
-				"DisableBrandingTrailer=%t, "+
-				"IgnoreInvalidResponse=%t, "+
-				"VerboseOutput=%t, "+
-				"SilentOutput=%t, "+
-				"ConvertEOL=%t, "+
-				"Base64EncodedWebhookURL=%t",
-			c.Team,
-			c.Channel,
-			c.webhookURL,
-			c.WebhookURL(),
-			c.ThemeColor,
-			c.MessageTitle,
-			c.MessageText,
-			c.Sender,
-			c.TargetURLs.String(),
-			strconv.Itoa(c.Retries),
-			strconv.Itoa(c.RetriesDelay),
-			c.TeamsSubmissionTimeout(),
-			c.DisableWebhookURLValidation,
-			c.DisableBrandingTrailer,
-			c.IgnoreInvalidResponse,
-			c.VerboseOutput,
-			c.SilentOutput,
-			c.ConvertEOL,
-			true,
-		)
+	settings := []struct {
+		name     string
+		flagName string
+		value    string
+	}{
+		{name: "Command", value: strconv.Quote(c.Command)},
+		{name: "ListenAddress", flagName: "listen", value: strconv.Quote(c.ListenAddress)},
+		{name: "Args", value: fmt.Sprintf("%q", c.Args)},
+		{name: "NotifyOn", flagName: "notify-on", value: strconv.Quote(c.NotifyOn)},
+		{name: "StateFile", flagName: "state-file", value: strconv.Quote(c.StateFile)},
+		{name: "SpoolDir", flagName: "spool-dir", value: strconv.Quote(c.SpoolDir)},
+		{name: "SpoolMaxAge", flagName: "spool-max-age", value: strconv.Quote(c.SpoolMaxAge.String())},
+		{name: "SpoolMaxAttempts", flagName: "spool-max-attempts", value: strconv.Quote(strconv.Itoa(c.SpoolMaxAttempts))},
+		{name: "Team", flagName: "team", value: strconv.Quote(c.Team)},
+		{name: "Channel", flagName: "channel", value: strconv.Quote(c.Channel)},
+		{name: "WebhookURLs (raw)", flagName: "url", value: fmt.Sprintf("%q", c.redactedRawWebhookURLs())},
+		{name: "WebhookURLs (decoded)", value: fmt.Sprintf("%q", c.RedactedWebhookURLs())},
+		{name: "URLFile", flagName: "url-file", value: strconv.Quote(c.URLFile)},
+		{name: "AllowInsecureURLFile", flagName: "allow-insecure-url-file", value: strconv.FormatBool(c.AllowInsecureURLFile)},
+		{name: "KeyFile", flagName: "key-file", value: strconv.Quote(c.KeyFile)},
+		{name: "FailurePolicy", flagName: "failure-policy", value: strconv.Quote(c.FailurePolicy)},
+		{name: "ThemeColor", flagName: "color", value: strconv.Quote(c.ThemeColor)},
+		{name: "MessageTitle", flagName: "title", value: strconv.Quote(c.MessageTitle)},
+		{name: "MessageText", flagName: "message", value: strconv.Quote(c.MessageText)},
+		{name: "MessageFile", flagName: "message-file", value: strconv.Quote(c.MessageFile)},
+		{name: "MessageFromStdin", flagName: "stdin", value: strconv.FormatBool(c.MessageFromStdin)},
+		{name: "CodeFile", flagName: "code-file", value: strconv.Quote(c.CodeFile)},
+		{name: "CodeFromStdin", flagName: "code-stdin", value: strconv.FormatBool(c.CodeFromStdin)},
+		{name: "CodeLanguage", flagName: "code-language", value: strconv.Quote(c.CodeLanguage)},
+		{name: "TableFile", flagName: "table-file", value: strconv.Quote(c.TableFile)},
+		{name: "TableFormat", flagName: "table-format", value: strconv.Quote(c.TableFormat)},
+		{name: "TableHeader", flagName: "table-header", value: strconv.FormatBool(c.TableHeader)},
+		{name: "TableGrid", flagName: "table-grid", value: strconv.FormatBool(c.TableGrid)},
+		{name: "TableMaxColumns", flagName: "table-max-columns", value: strconv.Quote(strconv.Itoa(c.TableMaxColumns))},
+		{name: "TableMaxRows", flagName: "table-max-rows", value: strconv.Quote(strconv.Itoa(c.TableMaxRows))},
+		{name: "PayloadFile", flagName: "payload-file", value: strconv.Quote(c.PayloadFile)},
+		{name: "InputFormat", flagName: "input-format", value: strconv.Quote(c.InputFormat)},
+		{name: "Template", flagName: "template", value: strconv.Quote(c.Template)},
+		{name: "TemplateFile", flagName: "template-file", value: strconv.Quote(c.TemplateFile)},
+		{name: "TemplateVars", flagName: "var", value: strconv.Quote(c.TemplateVars.String())},
+		{name: "TemplateData", flagName: "template-data", value: strconv.Quote(c.TemplateData)},
+		{name: "Sender", flagName: "sender", value: strconv.Quote(c.Sender)},
+		{name: "TargetURLs", flagName: "target-url", value: strconv.Quote(c.TargetURLs.String())},
+		{name: "UserMentions", flagName: "user-mention", value: strconv.Quote(c.UserMentions.String())},
+		{name: "Facts", flagName: "fact", value: strconv.Quote(c.Facts.String())},
+		{name: "Severity", flagName: "severity", value: strconv.Quote(c.Severity)},
+		{name: "NagiosMode", flagName: "nagios", value: strconv.FormatBool(c.NagiosMode)},
+		{name: "Icinga2Mode", flagName: "icinga2", value: strconv.FormatBool(c.Icinga2Mode)},
+		{name: "Icingaweb2URL", flagName: "icingaweb2-url", value: strconv.Quote(c.Icingaweb2URL)},
+		{name: "ZabbixMode", flagName: "zabbix", value: strconv.FormatBool(c.ZabbixMode)},
+		{name: "DryRun", flagName: "dry-run", value: strconv.FormatBool(c.DryRun)},
+		{name: "Oversize", flagName: "oversize", value: strconv.Quote(c.Oversize)},
+		{name: "Retries", flagName: "retries", value: strconv.Quote(strconv.Itoa(c.Retries))},
+		{name: "RetriesDelay", flagName: "retries-delay", value: strconv.Quote(strconv.Itoa(c.RetriesDelay))},
+		{name: "AppTimeout", value: strconv.Quote(c.TeamsSubmissionTimeout().String())},
+		{name: "DisableWebhookURLValidation", flagName: "disable-url-validation", value: strconv.FormatBool(c.DisableWebhookURLValidation)},
+		{name: "DisableBrandingTrailer", flagName: "disable-branding-trailer", value: strconv.FormatBool(c.DisableBrandingTrailer)},
+		{name: "IgnoreInvalidResponse", flagName: "ignore-invalid-response", value: strconv.FormatBool(c.IgnoreInvalidResponse)},
+		{name: "VerboseOutput", flagName: "verbose", value: strconv.FormatBool(c.VerboseOutput)},
+		{name: "SilentOutput", flagName: "silent", value: strconv.FormatBool(c.SilentOutput)},
+		{name: "ShowSecrets", flagName: "show-secrets", value: strconv.FormatBool(c.ShowSecrets)},
+		{name: "ConvertEOL", flagName: "convert-eol", value: strconv.FormatBool(c.ConvertEOL)},
+		{name: "ConfigFile", flagName: "config", value: strconv.Quote(c.ConfigFile)},
+		{name: "Destination", flagName: "destination", value: strconv.Quote(c.Destination)},
+		{name: "Base64EncodedWebhookURL", value: strconv.FormatBool(c.hasBase64WebhookURL())},
+		{name: "EncryptedWebhookURL", value: strconv.FormatBool(c.hasEncryptedWebhookURL())},
+	}
 
-	default:
-		return fmt.Sprintf(
-			"Team=%q, "+
-				"Channel=%q, "+
-				"WebhookURL=%q, "+
-				"ThemeColor=%q, "+
-				"MessageTitle=%q, "+
-				"MessageText=%q, "+
-				"Sender=%q, "+
-				"TargetURLs=%q, "+
-				"Retries=%q, "+
-				"RetriesDelay=%q, "+
-				"AppTimeout=%q, "+
-				"DisableWebhookURLValidation=%t, "+
-				"DisableBrandingTrailer=%t, "+
-				"IgnoreInvalidResponse=%t, "+
-				"VerboseOutput=%t, "+
-				"SilentOutput=%t, "+
-				"ConvertEOL=%t, "+
-				"Base64EncodedWebhookURL=%t",
-			c.Team,
-			c.Channel,
-			c.WebhookURL(),
-			c.ThemeColor,
-			c.MessageTitle,
-			c.MessageText,
-			c.Sender,
-			c.TargetURLs.String(),
-			strconv.Itoa(c.Retries),
-			strconv.Itoa(c.RetriesDelay),
-			c.TeamsSubmissionTimeout(),
-			c.DisableWebhookURLValidation,
-			c.DisableBrandingTrailer,
-			c.IgnoreInvalidResponse,
-			c.VerboseOutput,
-			c.SilentOutput,
-			c.ConvertEOL,
-			false,
-		)
+	items := make([]string, 0, len(settings))
+	for _, setting := range settings {
+		item := setting.name + "=" + setting.value
+		if setting.flagName != "" {
+			item += " (" + string(c.source(setting.flagName)) + ")"
+		}
+		items = append(items, item)
 	}
 
+	return strings.Join(items, ", ")
 }
 
 // NewConfig is a factory function that produces a new Config object based
@@ -524,6 +951,54 @@ func NewConfig() (*Config, error) {
 		return &cfg, ErrVersionRequested
 	}
 
+	if err := cfg.handleEnvConfig(); err != nil {
+		return nil, err
+	}
+
+	// The key is needed to validate encrypted webhook URLs for destinations
+	// defined in the configuration file.
+	if err := cfg.handleKeyFile(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleConfigFile(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleURLFile(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleMessageInput(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleCodeInput(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleTableInput(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleTemplate(); err != nil {
+		return nil, err
+	}
+
+	cfg.handleSeverity()
+
+	if err := cfg.handleNagiosMode(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleIcinga2Mode(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.handleZabbixMode(); err != nil {
+		return nil, err
+	}
+
 	// log.Debug("Validating configuration ...")
 	if err := cfg.Validate(cfg.DisableWebhookURLValidation); err != nil {
 		return nil, err
@@ -544,10 +1019,88 @@ func (c Config) Validate(disableWebhookURLValidation bool) error {
 		return fmt.Errorf("unsupported: You cannot have both silent and verbose output")
 	}
 
-	if c.MessageText == "" {
+	monitoringMode := len(c.notificationModes()) > 0
+
+	switch {
+	case c.conflictingModes():
+		return fmt.Errorf(
+			"unsupported: You cannot use more than one of Nagios, Icinga 2 or Zabbix modes; requested %s",
+			strings.Join(c.notificationModes(), ", "),
+		)
+
+	case c.Command == CommandServe &&
+		(c.MessageText != "" || len(c.Payload) > 0 || c.InputFormat != "" || monitoringMode || c.DryRun):
+		return fmt.Errorf(
+			"unsupported: You cannot use message, payload, input format, Nagios, Icinga 2, Zabbix or dry-run options with the %s command",
+			CommandServe,
+		)
+
+	case c.Command == CommandServe:
+		// Messages are submitted by clients of the serve command.
+
+	case c.Command == CommandExec &&
+		(len(c.Payload) > 0 || c.InputFormat != "" || monitoringMode):
+		return fmt.Errorf(
+			"unsupported: You cannot use payload, input format, Nagios, Icinga 2 or Zabbix options with the %s command",
+			CommandExec,
+		)
+
+	case c.Command == CommandExec:
+		// The message is generated from the result of the wrapped command.
+		if err := c.validateExec(); err != nil {
+			return err
+		}
+
+	case c.Command == CommandFlush &&
+		(c.MessageText != "" || len(c.Payload) > 0 || c.InputFormat != "" || monitoringMode || c.DryRun):
+		return fmt.Errorf(
+			"unsupported: You cannot use message, payload, input format, Nagios, Icinga 2, Zabbix or dry-run options with the %s command",
+			CommandFlush,
+		)
+
+	case c.Command == CommandFlush:
+		// Spooled messages are delivered to the webhook URL recorded for
+		// each message.
+		if err := c.validateSpool(); err != nil {
+			return err
+		}
+
+	case len(c.Payload) > 0 && monitoringMode:
+		return fmt.Errorf("unsupported: You cannot use a payload file with Nagios, Icinga 2 or Zabbix mode")
+
+	case len(c.Payload) > 0:
+		// The payload is validated as a Microsoft Teams message before
+		// submission.
+
+	case c.InputFormat != "" && !slices.Contains(supportedInputFormats(), c.InputFormat):
+		return fmt.Errorf(
+			"unsupported input format %q; expected one of %q",
+			c.InputFormat,
+			supportedInputFormats(),
+		)
+
+	case c.InputFormat != "" && monitoringMode:
+		return fmt.Errorf("unsupported: You cannot use an input format with Nagios, Icinga 2 or Zabbix mode")
+
+	case c.InputFormat != "" && len(c.Input) == 0:
+		return fmt.Errorf("%s notification content too short", c.InputFormat)
+
+	case c.InputFormat != "":
+		// The notification is validated when the message is generated.
+
+	// A code block or table may be submitted without message text.
+	case c.MessageText == "" && c.Code == "" && len(c.Table) == 0:
 		return fmt.Errorf("message content too short")
 	}
 
+	if err := c.validateCode(); err != nil {
+		return err
+	}
+
+	if err := c.validateTable(); err != nil {
+		return err
+	}
+
 	// Title is optional. If provided, use as-is.
 
 	// Team and Channel names are optional. If provided, use as-is.
@@ -562,17 +1115,87 @@ func (c Config) Validate(disableWebhookURLValidation bool) error {
 		return fmt.Errorf("retries delay too short")
 	}
 
+	switch c.FailurePolicy {
+	case FailurePolicyAny, FailurePolicyAll:
+	default:
+		return fmt.Errorf(
+			"unsupported failure policy %q; expected one of %q or %q",
+			c.FailurePolicy,
+			FailurePolicyAny,
+			FailurePolicyAll,
+		)
+	}
+
+	if c.Severity != "" && !slices.Contains(supportedSeverities(), c.Severity) {
+		return fmt.Errorf(
+			"unsupported severity %q; expected one of %q",
+			c.Severity,
+			supportedSeverities(),
+		)
+	}
+
+	if !slices.Contains(supportedOversizePolicies(), c.Oversize) {
+		return fmt.Errorf(
+			"unsupported oversize policy %q; expected one of %q",
+			c.Oversize,
+			supportedOversizePolicies(),
+		)
+	}
+
+	switch {
+	case len(c.webhookURLs) > 0:
+
+	// A webhook URL is not needed if the message is not being submitted.
+	case c.DryRun:
+
+	// Clients of the serve command may use webhook URLs from destinations
+	// defined in the configuration file.
+	case c.Command == CommandServe && len(c.destinations) > 0:
+
+	// Webhook URLs are recorded for each spooled message.
+	case c.Command == CommandFlush:
+
+	default:
+		return fmt.Errorf("webhook URL not specified")
+	}
+
+	if err := c.validateWebhookURLs(disableWebhookURLValidation); err != nil {
+		return err
+	}
+
+	// Indicate that we didn't spot any problems
+	return nil
+
+}
+
+// validateWebhookURLs asserts that each encrypted user-specified webhook URL
+// can be decrypted and that each user-specified webhook URL is valid unless
+// validation is disabled.
+func (c Config) validateWebhookURLs(disableWebhookURLValidation bool) error {
+	// Encrypted webhook URLs are unusable unless they can be decrypted, so
+	// they are checked even if validation is disabled.
+	if err := c.validateEncryptedWebhookURLs(); err != nil {
+		return err
+	}
+
+	// Allow selective toggling of webhook URL validation.
+	if disableWebhookURLValidation {
+		return nil
+	}
+
 	// Create Microsoft Teams client
 	mstClient := goteamsnotify.NewTeamsClient()
 
-	// Allow selective toggling of webhook URL validation.
-	if !disableWebhookURLValidation {
-		if err := mstClient.ValidateWebhook(c.WebhookURL()); err != nil {
-			return fmt.Errorf("webhook URL validation failed: %w", err)
+	for i, webhookURL := range c.WebhookURLs() {
+		if err := mstClient.ValidateWebhook(webhookURL); err != nil {
+			return c.RedactError(fmt.Errorf(
+				"webhook URL validation failed for webhook URL %d of %d: %w",
+				i+1,
+				len(c.webhookURLs),
+				err,
+			))
 		}
 	}
 
-	// Indicate that we didn't spot any problems
 	return nil
-
 }
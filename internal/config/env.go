@@ -0,0 +1,128 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envVarPrefix is the prefix used for all environment variables which
+// provide values for flags supported by this application.
+const envVarPrefix string = "SEND2TEAMS_"
+
+// valueSource indicates where the value for a configuration setting was
+// obtained.
+type valueSource string
+
+// Supported sources for configuration setting values, listed from lowest to
+// highest precedence.
+const (
+	sourceDefault    valueSource = "default"
+	sourceConfigFile valueSource = "config"
+	sourceEnv        valueSource = "env"
+	sourceFlag       valueSource = "flag"
+	sourceFile       valueSource = "file"
+	sourceStdin      valueSource = "stdin"
+	sourceTemplate   valueSource = "template"
+	sourceZabbix     valueSource = "zabbix"
+)
+
+// repeatableFlagValue is a flag.Value which accumulates values each time the
+// flag is specified. The reset method clears any accumulated values so that
+// a higher precedence source can replace (rather than extend) them.
+type repeatableFlagValue interface {
+	flag.Value
+	reset()
+}
+
+// envVarName returns the name of the environment variable which may be used
+// to provide a value for the specified flag (e.g., "retries-delay" is
+// provided by "SEND2TEAMS_RETRIES_DELAY").
+func envVarName(flagName string) string {
+	return envVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// skipEnvBinding indicates whether the given flag should not be settable via
+// environment variable. Version flags are excluded as they would prevent the
+// application from doing anything else, shorthand flags are excluded as
+// their long form counterparts are already bound.
+func skipEnvBinding(f *flag.Flag) bool {
+	switch {
+	case f.Name == "version":
+		return true
+	case strings.HasSuffix(f.Usage, shorthandFlagSuffix):
+		return true
+	default:
+		return false
+	}
+}
+
+// source returns the recorded source of the value for the specified flag.
+func (c Config) source(flagName string) valueSource {
+	if src, ok := c.valueSources[flagName]; ok {
+		return src
+	}
+
+	return sourceDefault
+}
+
+// setByUser indicates whether the value for the specified flag was provided
+// by a source with higher precedence than a configuration file.
+func (c Config) setByUser(flagName string) bool {
+	switch c.source(flagName) {
+	case sourceDefault, sourceConfigFile:
+		return false
+	default:
+		return true
+	}
+}
+
+// handleEnvConfig applies values from SEND2TEAMS_* environment variables for
+// each flag not explicitly specified via the command-line. Empty environment
+// variables are ignored. For repeatable flags (e.g., target-url) each line
+// of the environment variable value is applied as a separate flag value.
+func (c *Config) handleEnvConfig() error {
+	var err error
+
+	flag.VisitAll(func(f *flag.Flag) {
+		if err != nil || skipEnvBinding(f) || c.setByUser(f.Name) {
+			return
+		}
+
+		envVar := envVarName(f.Name)
+		val, ok := os.LookupEnv(envVar)
+		if !ok || strings.TrimSpace(val) == "" {
+			return
+		}
+
+		vals := []string{val}
+		if _, repeatable := f.Value.(repeatableFlagValue); repeatable {
+			vals = strings.Split(strings.TrimSpace(val), "\n")
+		}
+
+		for _, v := range vals {
+			if setErr := f.Value.Set(strings.TrimSpace(v)); setErr != nil {
+				err = fmt.Errorf(
+					"invalid value %q for environment variable %s: %w",
+					v,
+					envVar,
+					setErr,
+				)
+
+				return
+			}
+		}
+
+		c.valueSources[f.Name] = sourceEnv
+	})
+
+	return err
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testWebhookURL string = "https://default216c138bf5fd4aa8bf44fc3cb5a093.be.environment.api.powerplatform.com:443/powerautomate/automations/direct/workflows/ed3386c459104b11bd4e891c76e5e2a1/triggers/manual/paths/invoke?api-version=1&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=vqF0En-Z0ucuRTM01o2GuhMH3hKKkN2bOmlM31zaA"
+
+// newTestConfig creates a configuration from the given command-line
+// arguments using a new command-line flag set so that NewConfig may be
+// called more than once. The original arguments and flag set are restored
+// when the test completes.
+func newTestConfig(t *testing.T, args ...string) (*Config, error) {
+	t.Helper()
+
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	os.Args = append([]string{"send2teams"}, args...)
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	return NewConfig()
+}
+
+func TestEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"url":                      "SEND2TEAMS_URL",
+		"retries-delay":            "SEND2TEAMS_RETRIES_DELAY",
+		"disable-branding-trailer": "SEND2TEAMS_DISABLE_BRANDING_TRAILER",
+	}
+
+	for flagName, expected := range tests {
+		t.Run(flagName, func(t *testing.T) {
+			if got := envVarName(flagName); got != expected {
+				t.Errorf("got %q; expected %q", got, expected)
+			}
+		})
+	}
+}
+
+// TestConfigPrecedence asserts that values specified via flag take
+// precedence over environment variables, which take precedence over the
+// configuration file, which takes precedence over default values.
+func TestConfigPrecedence(t *testing.T) {
+	configFile := writeConfigFile(t, "config.toml", `
+[defaults]
+team = "file team"
+retries = 4
+fact = ["Source,file"]
+`)
+
+	tests := map[string]struct {
+		args            []string
+		env             map[string]string
+		useConfigFile   bool
+		expectedTeam    string
+		expectedRetries int
+		expectedFacts   []Fact
+		expectedSource  valueSource
+	}{
+		"default": {
+			expectedTeam:    defaultTeamName,
+			expectedRetries: defaultRetries,
+			expectedSource:  sourceDefault,
+		},
+		"configuration file over default": {
+			useConfigFile:   true,
+			expectedTeam:    "file team",
+			expectedRetries: 4,
+			expectedFacts:   []Fact{{Title: "Source", Value: "file"}},
+			expectedSource:  sourceConfigFile,
+		},
+		"environment variable over configuration file": {
+			env: map[string]string{
+				"SEND2TEAMS_TEAM": "env team",
+				"SEND2TEAMS_FACT": "Source,env\nExtra,env",
+			},
+			useConfigFile:   true,
+			expectedTeam:    "env team",
+			expectedRetries: 4,
+			expectedFacts:   []Fact{{Title: "Source", Value: "env"}, {Title: "Extra", Value: "env"}},
+			expectedSource:  sourceEnv,
+		},
+		"flag over environment variable": {
+			args: []string{"--team", "flag team", "--fact", "Source,flag"},
+			env: map[string]string{
+				"SEND2TEAMS_TEAM":    "env team",
+				"SEND2TEAMS_RETRIES": "3",
+				"SEND2TEAMS_FACT":    "Source,env",
+			},
+			useConfigFile:   true,
+			expectedTeam:    "flag team",
+			expectedRetries: 3,
+			expectedFacts:   []Fact{{Title: "Source", Value: "flag"}},
+			expectedSource:  sourceFlag,
+		},
+		"empty environment variable ignored": {
+			env:             map[string]string{"SEND2TEAMS_TEAM": "  "},
+			useConfigFile:   true,
+			expectedTeam:    "file team",
+			expectedRetries: 4,
+			expectedFacts:   []Fact{{Title: "Source", Value: "file"}},
+			expectedSource:  sourceConfigFile,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for envVar, value := range tt.env {
+				t.Setenv(envVar, value)
+			}
+
+			args := []string{"--url", testWebhookURL, "--message", "test"}
+			if tt.useConfigFile {
+				args = append(args, "--config", configFile)
+			}
+
+			cfg, err := newTestConfig(t, append(args, tt.args...)...)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if cfg.Team != tt.expectedTeam {
+				t.Errorf("got team %q; expected %q", cfg.Team, tt.expectedTeam)
+			}
+
+			if cfg.Retries != tt.expectedRetries {
+				t.Errorf("got retries %d; expected %d", cfg.Retries, tt.expectedRetries)
+			}
+
+			var facts []Fact
+			if len(cfg.Facts) > 0 {
+				facts = cfg.Facts
+			}
+			if !reflect.DeepEqual(facts, tt.expectedFacts) {
+				t.Errorf("got facts %v; expected %v", facts, tt.expectedFacts)
+			}
+
+			if got := cfg.source("team"); got != tt.expectedSource {
+				t.Errorf("got team source %q; expected %q", got, tt.expectedSource)
+			}
+		})
+	}
+}
+
+func TestConfigInvalidEnvValue(t *testing.T) {
+	t.Setenv("SEND2TEAMS_RETRIES", "many")
+
+	_, err := newTestConfig(t, "--url", testWebhookURL, "--message", "test")
+	if err == nil || !strings.Contains(err.Error(), "SEND2TEAMS_RETRIES") {
+		t.Fatalf("got %v; expected error naming the environment variable", err)
+	}
+}
+
+func TestConfigVersionNotBoundToEnv(t *testing.T) {
+	t.Setenv("SEND2TEAMS_VERSION", "true")
+
+	cfg, err := newTestConfig(t, "--url", testWebhookURL, "--message", "test")
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if cfg.ShowVersion {
+		t.Errorf("got true; expected version flag not to be set via environment variable")
+	}
+}
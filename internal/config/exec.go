@@ -0,0 +1,56 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Supported policies used by the exec command to determine whether a
+// message is submitted for the result of the wrapped command.
+const (
+	// NotifyOnAlways indicates that a message is submitted for every result.
+	NotifyOnAlways string = "always"
+
+	// NotifyOnFailure indicates that a message is only submitted when the
+	// command fails.
+	NotifyOnFailure string = "failure"
+
+	// NotifyOnChange indicates that a message is only submitted when the
+	// result differs from the result of the previous run.
+	NotifyOnChange string = "change"
+)
+
+// supportedNotifyOnPolicies returns the list of supported notify-on
+// policies.
+func supportedNotifyOnPolicies() []string {
+	return []string{
+		NotifyOnAlways,
+		NotifyOnFailure,
+		NotifyOnChange,
+	}
+}
+
+// validateExec asserts that the settings used by the exec command are
+// valid.
+func (c Config) validateExec() error {
+	switch {
+	case len(c.Args) == 0:
+		return fmt.Errorf("command to run not specified for the %s command", CommandExec)
+
+	case !slices.Contains(supportedNotifyOnPolicies(), c.NotifyOn):
+		return fmt.Errorf(
+			"unsupported notify-on policy %q; expected one of %q",
+			c.NotifyOn,
+			supportedNotifyOnPolicies(),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrDestinationNotFound indicates that the user-specified destination is
+// not defined in the configuration file.
+var ErrDestinationNotFound = errors.New("destination not found in configuration file")
+
+// ErrUnsupportedConfigFileFormat indicates that the format of the
+// configuration file could not be determined from the file extension.
+var ErrUnsupportedConfigFileFormat = errors.New("unsupported configuration file format")
+
+// ErrConfigFileRequired indicates that a setting was specified which requires
+// a configuration file, but a configuration file was not specified.
+var ErrConfigFileRequired = errors.New("configuration file required")
+
+// fileSettings is the collection of settings which may be specified in a
+// configuration file (in any supported format). Each field is named after
+// the flag it provides a value for. Pointer types are used so that settings
+// omitted from the file can be distinguished from settings explicitly set to
+// a zero value.
+type fileSettings struct {
+	WebhookURLs                 stringList `json:"url" toml:"url" yaml:"url"`
+	Team                        *string    `json:"team" toml:"team" yaml:"team"`
+	Channel                     *string    `json:"channel" toml:"channel" yaml:"channel"`
+	Sender                      *string    `json:"sender" toml:"sender" yaml:"sender"`
+	Retries                     *int       `json:"retries" toml:"retries" yaml:"retries"`
+	RetriesDelay                *int       `json:"retries-delay" toml:"retries-delay" yaml:"retries-delay"`
+	DisableWebhookURLValidation *bool      `json:"disable-url-validation" toml:"disable-url-validation" yaml:"disable-url-validation"`
+	DisableBrandingTrailer      *bool      `json:"disable-branding-trailer" toml:"disable-branding-trailer" yaml:"disable-branding-trailer"`
+	IgnoreInvalidResponse       *bool      `json:"ignore-invalid-response" toml:"ignore-invalid-response" yaml:"ignore-invalid-response"`
+	ConvertEOL                  *bool      `json:"convert-eol" toml:"convert-eol" yaml:"convert-eol"`
+	TargetURLs                  stringList `json:"target-url" toml:"target-url" yaml:"target-url"`
+	UserMentions                stringList `json:"user-mention" toml:"user-mention" yaml:"user-mention"`
+	Facts                       stringList `json:"fact" toml:"fact" yaml:"fact"`
+}
+
+// stringList is a collection of values for a repeatable flag. A single
+// string value is also accepted for convenience.
+type stringList []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting either
+// a single string value or a list of string values.
+func (sl *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*sl = stringList{single}
+
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected string or list of strings: %w", err)
+	}
+	*sl = list
+
+	return nil
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface, accepting either
+// a single string value or a list of string values.
+func (sl *stringList) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		*sl = stringList{v}
+
+		return nil
+
+	case []interface{}:
+		list := make(stringList, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected string or list of strings, got list containing %T", item)
+			}
+			list = append(list, s)
+		}
+		*sl = list
+
+		return nil
+
+	default:
+		return fmt.Errorf("expected string or list of strings, got %T", data)
+	}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting either
+// a single string value or a list of string values.
+func (sl *stringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var single string
+		if err := value.Decode(&single); err != nil {
+			return err
+		}
+		*sl = stringList{single}
+
+		return nil
+	}
+
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return fmt.Errorf("expected string or list of strings: %w", err)
+	}
+	*sl = list
+
+	return nil
+}
+
+// fileConfig represents the contents of a configuration file. Settings in
+// the Defaults section apply to every destination. Settings for a named
+// destination take precedence over settings in the Defaults section.
+type fileConfig struct {
+	Defaults     fileSettings            `json:"defaults" toml:"defaults" yaml:"defaults"`
+	Destinations map[string]fileSettings `json:"destinations" toml:"destinations" yaml:"destinations"`
+}
+
+// loadConfigFile reads and parses the specified configuration file. The
+// format of the file is determined by the file extension: .toml for TOML,
+// .yaml or .yml for YAML and .json for JSON. Unknown settings are rejected
+// in order to catch typos early.
+func loadConfigFile(filename string) (fileConfig, error) {
+	// #nosec G304 -- file path is intentionally provided by the user
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fileConfig{}, fmt.Errorf("failed to open configuration file: %w", err)
+	}
+
+	var fc fileConfig
+
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".toml":
+		err = decodeTOMLConfig(data, &fc)
+	case ".yaml", ".yml":
+		err = decodeYAMLConfig(data, &fc)
+	case ".json":
+		err = decodeJSONConfig(data, &fc)
+	default:
+		return fileConfig{}, fmt.Errorf(
+			"%w: %q (use a .toml, .yaml, .yml or .json file extension)",
+			ErrUnsupportedConfigFileFormat,
+			ext,
+		)
+	}
+
+	if err != nil {
+		return fileConfig{}, fmt.Errorf(
+			"failed to parse configuration file %s: %w",
+			filename,
+			err,
+		)
+	}
+
+	return fc, nil
+}
+
+// decodeJSONConfig decodes the given JSON configuration file contents.
+func decodeJSONConfig(data []byte, fc *fileConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(fc)
+}
+
+// decodeTOMLConfig decodes the given TOML configuration file contents.
+func decodeTOMLConfig(data []byte, fc *fileConfig) error {
+	md, err := toml.Decode(string(data), fc)
+	if err != nil {
+		return err
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, strconv.Quote(key.String()))
+		}
+
+		return fmt.Errorf("unknown settings %s", strings.Join(keys, ", "))
+	}
+
+	return nil
+}
+
+// decodeYAMLConfig decodes the given YAML configuration file contents. An
+// empty file is accepted.
+func decodeYAMLConfig(data []byte, fc *fileConfig) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(fc); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+// destinationNames returns a sorted list of destinations defined in the
+// configuration file.
+func (fc fileConfig) destinationNames() []string {
+	names := make([]string, 0, len(fc.Destinations))
+	for name := range fc.Destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// handleConfigFile applies settings from the user-specified configuration
+// file. Settings from the Defaults section are applied first, followed by
+// settings for the user-specified destination (if any). Settings specified
+// via flag or environment variable are not overridden.
+func (c *Config) handleConfigFile() error {
+	if c.ConfigFile == "" {
+		if c.Destination != "" {
+			return fmt.Errorf(
+				"%w: destination %q specified without configuration file",
+				ErrConfigFileRequired,
+				c.Destination,
+			)
+		}
+
+		return nil
+	}
+
+	fc, err := loadConfigFile(c.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if err := c.applyFileSettings(fc.Defaults); err != nil {
+		return fmt.Errorf("failed to apply default settings from configuration file: %w", err)
+	}
+
+	// Every destination is made available to clients of the serve command.
+	if c.Command == CommandServe {
+		if err := c.loadDestinations(fc); err != nil {
+			return err
+		}
+	}
+
+	if c.Destination == "" {
+		return nil
+	}
+
+	settings, ok := fc.Destinations[c.Destination]
+	if !ok {
+		return fmt.Errorf(
+			"%w: %q (available: %s)",
+			ErrDestinationNotFound,
+			c.Destination,
+			strings.Join(fc.destinationNames(), ", "),
+		)
+	}
+
+	if err := c.applyFileSettings(settings); err != nil {
+		return fmt.Errorf(
+			"failed to apply settings for destination %q from configuration file: %w",
+			c.Destination,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// fileSettingFlags returns a flag set whose flags are bound to the fields of
+// this configuration for each setting which may be specified in a
+// configuration file. This allows settings to be applied to a copy of the
+// configuration (e.g., for a destination) using the same flag.Value logic
+// used when parsing command-line flags.
+func (c *Config) fileSettingFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("configuration file", flag.ContinueOnError)
+
+	// The current values are used as defaults so that binding the flags
+	// does not modify the configuration.
+	fs.StringVar(&c.Team, "team", c.Team, teamNameFlagHelp)
+	fs.StringVar(&c.Channel, "channel", c.Channel, channelNameFlagHelp)
+	fs.StringVar(&c.Sender, "sender", c.Sender, senderFlagHelp)
+	fs.IntVar(&c.Retries, "retries", c.Retries, retriesFlagHelp)
+	fs.IntVar(&c.RetriesDelay, "retries-delay", c.RetriesDelay, retriesDelayFlagHelp)
+	fs.BoolVar(&c.DisableWebhookURLValidation, "disable-url-validation", c.DisableWebhookURLValidation, disableWebhookURLValidationFlagHelp)
+	fs.BoolVar(&c.DisableBrandingTrailer, "disable-branding-trailer", c.DisableBrandingTrailer, disableBrandingTrailerFlagHelp)
+	fs.BoolVar(&c.IgnoreInvalidResponse, "ignore-invalid-response", c.IgnoreInvalidResponse, ignoreInvalidResponseFlagHelp)
+	fs.BoolVar(&c.ConvertEOL, "convert-eol", c.ConvertEOL, convertEOLFlagHelp)
+	fs.Var(&c.webhookURLs, "url", webhookURLFlagHelp)
+	fs.Var(&c.TargetURLs, "target-url", targetURLFlagHelp)
+	fs.Var(&c.UserMentions, "user-mention", userMentionFlagHelp)
+	fs.Var(&c.Facts, "fact", factFlagHelp)
+
+	return fs
+}
+
+// applyFileSettings applies the given settings for each flag whose value was
+// not specified via flag or environment variable. Values are applied using
+// the same flag.Value logic used when parsing command-line flags.
+func (c *Config) applyFileSettings(s fileSettings) error {
+	values := make(map[string][]string)
+
+	addString := func(name string, val *string) {
+		if val != nil {
+			values[name] = []string{*val}
+		}
+	}
+	addInt := func(name string, val *int) {
+		if val != nil {
+			values[name] = []string{strconv.Itoa(*val)}
+		}
+	}
+	addBool := func(name string, val *bool) {
+		if val != nil {
+			values[name] = []string{strconv.FormatBool(*val)}
+		}
+	}
+
+	addString("team", s.Team)
+	addString("channel", s.Channel)
+	addString("sender", s.Sender)
+	addInt("retries", s.Retries)
+	addInt("retries-delay", s.RetriesDelay)
+	addBool("disable-url-validation", s.DisableWebhookURLValidation)
+	addBool("disable-branding-trailer", s.DisableBrandingTrailer)
+	addBool("ignore-invalid-response", s.IgnoreInvalidResponse)
+	addBool("convert-eol", s.ConvertEOL)
+
+	if len(s.WebhookURLs) > 0 {
+		values["url"] = s.WebhookURLs
+	}
+	if len(s.TargetURLs) > 0 {
+		values["target-url"] = s.TargetURLs
+	}
+	if len(s.UserMentions) > 0 {
+		values["user-mention"] = s.UserMentions
+	}
+	if len(s.Facts) > 0 {
+		values["fact"] = s.Facts
+	}
+
+	fs := c.fileSettingFlags()
+
+	for name, vals := range values {
+		if c.setByUser(name) {
+			continue
+		}
+
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("unknown setting %q", name)
+		}
+
+		// Settings from a destination replace (rather than extend) any
+		// repeatable values applied from the Defaults section.
+		if v, ok := f.Value.(repeatableFlagValue); ok {
+			v.reset()
+		}
+
+		for _, val := range vals {
+			if err := f.Value.Set(val); err != nil {
+				return fmt.Errorf("invalid value %q for setting %q: %w", val, name, err)
+			}
+		}
+
+		c.valueSources[name] = sourceConfigFile
+	}
+
+	return nil
+}
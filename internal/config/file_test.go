@@ -0,0 +1,277 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfigFile writes the given contents to a configuration file with the
+// given name in a temporary directory, returning the path to the file.
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write configuration file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	team := "Operations"
+	retries := 5
+	disableTrailer := true
+
+	expected := fileConfig{
+		Defaults: fileSettings{
+			Team:                   &team,
+			Retries:                &retries,
+			DisableBrandingTrailer: &disableTrailer,
+			WebhookURLs:            stringList{"https://example.com/default"},
+		},
+		Destinations: map[string]fileSettings{
+			"oncall": {
+				WebhookURLs: stringList{"https://example.com/a", "https://example.com/b"},
+				Facts:       stringList{"Team,ops"},
+			},
+		},
+	}
+
+	tests := map[string]string{
+		"config.toml": `
+[defaults]
+team = "Operations"
+retries = 5
+disable-branding-trailer = true
+url = "https://example.com/default"
+
+[destinations.oncall]
+url = ["https://example.com/a", "https://example.com/b"]
+fact = "Team,ops"
+`,
+		"config.yaml": `
+defaults:
+  team: Operations
+  retries: 5
+  disable-branding-trailer: true
+  url: https://example.com/default
+destinations:
+  oncall:
+    url:
+      - https://example.com/a
+      - https://example.com/b
+    fact: Team,ops
+`,
+		"config.YML": `
+defaults: {team: Operations, retries: 5, disable-branding-trailer: true, url: "https://example.com/default"}
+destinations:
+  oncall: {url: ["https://example.com/a", "https://example.com/b"], fact: "Team,ops"}
+`,
+		"config.json": `{
+  "defaults": {
+    "team": "Operations",
+    "retries": 5,
+    "disable-branding-trailer": true,
+    "url": "https://example.com/default"
+  },
+  "destinations": {
+    "oncall": {
+      "url": ["https://example.com/a", "https://example.com/b"],
+      "fact": "Team,ops"
+    }
+  }
+}`,
+	}
+
+	for name, contents := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := loadConfigFile(writeConfigFile(t, name, contents))
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("got %+v; expected %+v", got, expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		name        string
+		contents    string
+		expectedErr error
+		expectedMsg string
+	}{
+		"unknown TOML setting": {
+			name:        "config.toml",
+			contents:    "[defaults]\nteem = \"Operations\"\n",
+			expectedMsg: "teem",
+		},
+		"unknown YAML setting": {
+			name:        "config.yaml",
+			contents:    "defaults:\n  teem: Operations\n",
+			expectedMsg: "teem",
+		},
+		"unknown JSON setting": {
+			name:        "config.json",
+			contents:    `{"defaults": {"teem": "Operations"}}`,
+			expectedMsg: "teem",
+		},
+		"invalid list item": {
+			name:        "config.toml",
+			contents:    "[defaults]\nurl = [1, 2]\n",
+			expectedMsg: "expected string or list of strings",
+		},
+		"unsupported extension": {
+			name:        "config.ini",
+			contents:    "team=Operations\n",
+			expectedErr: ErrUnsupportedConfigFileFormat,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadConfigFile(writeConfigFile(t, tt.name, tt.contents))
+
+			switch {
+			case err == nil:
+				t.Fatalf("got nil; expected error")
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case !strings.Contains(err.Error(), tt.expectedMsg):
+				t.Fatalf("got %v; expected error mentioning %q", err, tt.expectedMsg)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileEmptyYAML(t *testing.T) {
+	got, err := loadConfigFile(writeConfigFile(t, "config.yaml", ""))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if !reflect.DeepEqual(got, fileConfig{}) {
+		t.Errorf("got %+v; expected empty configuration", got)
+	}
+}
+
+func TestApplyFileSettings(t *testing.T) {
+	team := "From file"
+	channel := "Alerts"
+	retries := 7
+
+	c := Config{
+		Team:        "From flag",
+		Channel:     "General",
+		Retries:     2,
+		webhookURLs: webhookURLsStringFlag{"https://example.com/flag"},
+		valueSources: map[string]valueSource{
+			"team": sourceFlag,
+			"url":  sourceEnv,
+		},
+	}
+
+	err := c.applyFileSettings(fileSettings{
+		Team:        &team,
+		Channel:     &channel,
+		Retries:     &retries,
+		WebhookURLs: stringList{"https://example.com/file"},
+		Facts:       stringList{"Team,ops"},
+	})
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	switch {
+	case c.Team != "From flag":
+		t.Errorf("got team %q; expected value set via flag to be retained", c.Team)
+	case !reflect.DeepEqual([]string(c.webhookURLs), []string{"https://example.com/flag"}):
+		t.Errorf("got webhook URLs %q; expected values set via environment variable to be retained", c.webhookURLs)
+	case c.Channel != "Alerts":
+		t.Errorf("got channel %q; expected %q", c.Channel, "Alerts")
+	case c.Retries != 7:
+		t.Errorf("got retries %d; expected 7", c.Retries)
+	case len(c.Facts) != 1 || c.Facts[0].Title != "Team":
+		t.Errorf("got facts %v; expected fact from configuration file", c.Facts)
+	case c.source("channel") != sourceConfigFile:
+		t.Errorf("got source %q; expected %q", c.source("channel"), sourceConfigFile)
+	}
+}
+
+func TestApplyFileSettingsDestinationReplacesDefaults(t *testing.T) {
+	c := Config{valueSources: make(map[string]valueSource)}
+
+	if err := c.applyFileSettings(fileSettings{Facts: stringList{"Team,ops", "Site,hq"}}); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if err := c.applyFileSettings(fileSettings{Facts: stringList{"Team,db"}}); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	expected := factsStringFlag{{Title: "Team", Value: "db"}}
+	if !reflect.DeepEqual(c.Facts, expected) {
+		t.Errorf("got %v; expected destination facts %v to replace default facts", c.Facts, expected)
+	}
+}
+
+func TestLoadDestinationsDoesNotModifyConfig(t *testing.T) {
+	team := "Database"
+
+	c := Config{
+		Team:         "Operations",
+		webhookURLs:  webhookURLsStringFlag{"https://example.com/default"},
+		valueSources: map[string]valueSource{"url": sourceConfigFile},
+	}
+
+	fc := fileConfig{
+		Destinations: map[string]fileSettings{
+			"db": {
+				Team:                        &team,
+				WebhookURLs:                 stringList{"https://example.com/db"},
+				DisableWebhookURLValidation: boolPtr(true),
+			},
+		},
+	}
+
+	if err := c.loadDestinations(fc); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if c.Team != "Operations" || !reflect.DeepEqual([]string(c.webhookURLs), []string{"https://example.com/default"}) {
+		t.Errorf("got team %q and webhook URLs %q; expected default settings to be unmodified", c.Team, c.webhookURLs)
+	}
+
+	dest, err := c.MessageConfig("db")
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if dest.Team != "Database" || !reflect.DeepEqual([]string(dest.webhookURLs), []string{"https://example.com/db"}) {
+		t.Errorf("got team %q and webhook URLs %q; expected destination settings", dest.Team, dest.webhookURLs)
+	}
+
+	if _, err := c.MessageConfig("missing"); !errors.Is(err, ErrDestinationNotFound) {
+		t.Errorf("got %v; expected error %q", err, ErrDestinationNotFound)
+	}
+}
+
+// boolPtr returns a pointer to the given value.
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -7,7 +7,10 @@
 
 package config
 
-import "flag"
+import (
+	"flag"
+	"os"
+)
 
 // handleFlagsConfig wraps flag setup code into a bundle for potential ease of
 // use and future testability
@@ -15,6 +18,7 @@ func (c *Config) handleFlagsConfig() {
 
 	flag.BoolVar(&c.VerboseOutput, "verbose", defaultVerboseOutput, verboseOutputFlagHelp)
 	flag.BoolVar(&c.SilentOutput, "silent", defaultSilentOutput, silentOutputFlagHelp)
+	flag.BoolVar(&c.ShowSecrets, "show-secrets", defaultShowSecrets, showSecretsFlagHelp)
 	flag.BoolVar(&c.ConvertEOL, "convert-eol", defaultConvertEOL, convertEOLFlagHelp)
 	flag.BoolVar(&c.DisableWebhookURLValidation, "disable-url-validation", defaultDisableWebhookURLValidation, disableWebhookURLValidationFlagHelp)
 	flag.BoolVar(&c.DisableBrandingTrailer, "disable-branding-trailer", defaultDisableBrandingTrailer, disableBrandingTrailerFlagHelp)
@@ -22,20 +26,70 @@ func (c *Config) handleFlagsConfig() {
 	flag.StringVar(&c.Team, "team", defaultTeamName, teamNameFlagHelp)
 	flag.Var(&c.TargetURLs, "target-url", targetURLFlagHelp)
 	flag.Var(&c.UserMentions, "user-mention", userMentionFlagHelp)
+	flag.Var(&c.Facts, "fact", factFlagHelp)
 	flag.StringVar(&c.Channel, "channel", defaultChannelName, channelNameFlagHelp)
-	flag.StringVar(&c.webhookURL, "url", defaultWebhookURL, webhookURLFlagHelp)
+	flag.Var(&c.webhookURLs, "url", webhookURLFlagHelp)
+	flag.StringVar(&c.URLFile, "url-file", defaultURLFile, urlFileFlagHelp)
+	flag.BoolVar(&c.AllowInsecureURLFile, "allow-insecure-url-file", defaultAllowInsecureURLFile, allowInsecureURLFileFlagHelp)
+	flag.StringVar(&c.KeyFile, "key-file", defaultKeyFile, keyFileFlagHelp)
+	flag.StringVar(&c.FailurePolicy, "failure-policy", defaultFailurePolicy, failurePolicyFlagHelp)
 	flag.StringVar(&c.ThemeColor, "color", defaultMessageThemeColor, themeColorFlagHelp)
+	flag.StringVar(&c.Severity, "severity", defaultSeverity, severityFlagHelp)
 	flag.StringVar(&c.MessageTitle, "title", defaultMessageTitle, titleFlagHelp)
 	flag.StringVar(&c.MessageText, "message", defaultMessageText, messageFlagHelp)
+	flag.StringVar(&c.MessageFile, "message-file", defaultMessageFile, messageFileFlagHelp)
+	flag.BoolVar(&c.MessageFromStdin, "stdin", defaultMessageFromStdin, messageFromStdinFlagHelp)
+	flag.StringVar(&c.CodeFile, "code-file", defaultCodeFile, codeFileFlagHelp)
+	flag.BoolVar(&c.CodeFromStdin, "code-stdin", defaultCodeFromStdin, codeFromStdinFlagHelp)
+	flag.StringVar(&c.CodeLanguage, "code-language", defaultCodeLanguage, codeLanguageFlagHelp)
+	flag.StringVar(&c.TableFile, "table-file", defaultTableFile, tableFileFlagHelp)
+	flag.StringVar(&c.TableFormat, "table-format", defaultTableFormat, tableFormatFlagHelp)
+	flag.BoolVar(&c.TableHeader, "table-header", defaultTableHeader, tableHeaderFlagHelp)
+	flag.BoolVar(&c.TableGrid, "table-grid", defaultTableGrid, tableGridFlagHelp)
+	flag.IntVar(&c.TableMaxColumns, "table-max-columns", defaultTableMaxColumns, tableMaxColumnsFlagHelp)
+	flag.IntVar(&c.TableMaxRows, "table-max-rows", defaultTableMaxRows, tableMaxRowsFlagHelp)
+	flag.StringVar(&c.PayloadFile, "payload-file", defaultPayloadFile, payloadFileFlagHelp)
+	flag.StringVar(&c.InputFormat, "input-format", defaultInputFormat, inputFormatFlagHelp)
+	flag.StringVar(&c.Template, "template", defaultTemplate, templateFlagHelp)
+	flag.StringVar(&c.TemplateFile, "template-file", defaultTemplateFile, templateFileFlagHelp)
+	flag.Var(&c.TemplateVars, "var", templateVarFlagHelp)
+	flag.StringVar(&c.TemplateData, "template-data", defaultTemplateData, templateDataFlagHelp)
 	flag.StringVar(&c.Sender, "sender", defaultSender, senderFlagHelp)
 	flag.IntVar(&c.Retries, "retries", defaultRetries, retriesFlagHelp)
 	flag.IntVar(&c.RetriesDelay, "retries-delay", defaultRetriesDelay, retriesDelayFlagHelp)
+	flag.BoolVar(&c.NagiosMode, "nagios", defaultNagiosMode, nagiosModeFlagHelp)
+	flag.BoolVar(&c.Icinga2Mode, "icinga2", defaultIcinga2Mode, icinga2ModeFlagHelp)
+	flag.StringVar(&c.Icingaweb2URL, "icingaweb2-url", defaultIcingaweb2URL, icingaweb2URLFlagHelp)
+	flag.BoolVar(&c.ZabbixMode, "zabbix", defaultZabbixMode, zabbixModeFlagHelp)
+	flag.BoolVar(&c.DryRun, "dry-run", defaultDryRun, dryRunFlagHelp)
+	flag.StringVar(&c.Oversize, "oversize", defaultOversize, oversizeFlagHelp)
+	flag.StringVar(&c.ListenAddress, "listen", defaultListenAddress, listenFlagHelp)
+	flag.StringVar(&c.NotifyOn, "notify-on", defaultNotifyOn, notifyOnFlagHelp)
+	flag.StringVar(&c.StateFile, "state-file", defaultStateFile, stateFileFlagHelp)
+	flag.StringVar(&c.SpoolDir, "spool-dir", defaultSpoolDir, spoolDirFlagHelp)
+	flag.DurationVar(&c.SpoolMaxAge, "spool-max-age", defaultSpoolMaxAge, spoolMaxAgeFlagHelp)
+	flag.IntVar(&c.SpoolMaxAttempts, "spool-max-attempts", defaultSpoolMaxAttempts, spoolMaxAttemptsFlagHelp)
+	flag.StringVar(&c.ConfigFile, "config", defaultConfigFile, configFileFlagHelp)
+	flag.StringVar(&c.Destination, "destination", defaultDestination, destinationFlagHelp)
 	flag.BoolVar(&c.ShowVersion, "version", defaultDisplayVersionAndExit, versionFlagHelp)
 	flag.BoolVar(&c.ShowVersion, "v", defaultDisplayVersionAndExit, versionFlagHelp+shorthandFlagSuffix)
 
 	flag.Usage = flagsUsage()
 
-	// parse flag definitions from the argument list
-	flag.Parse()
+	// The subcommand (if any) precedes all flags.
+	var args []string
+	c.Command, args = splitCommand(os.Args[1:])
+
+	// parse flag definitions from the argument list; flag.CommandLine exits
+	// on error in the same way as flag.Parse
+	_ = flag.CommandLine.Parse(args)
+	c.Args = flag.Args()
+
+	// Record which flags were explicitly specified so that values from other
+	// configuration sources do not override them.
+	c.valueSources = make(map[string]valueSource)
+	flag.Visit(func(f *flag.Flag) {
+		c.valueSources[f.Name] = sourceFlag
+	})
 
 }
@@ -43,20 +43,98 @@ func (c Config) UserAgent() string {
 
 }
 
-// WebhookURL attempts to transparently process the given input for the target
-// Microsoft Teams webhook URL as:
+// WebhookURL attempts to transparently process the given input for the first
+// target Microsoft Teams webhook URL as:
 //
+//   - an encrypted webhook URL (if a key file was specified)
 //   - a single base64 string
 //   - multiple base64 strings ("segments") separated by commas
 //   - an unencoded webhook URL
 //
-// If a decode attempt is successful, the decoded value is used for message
-// delivery. If unsuccessful the original input value is provided as-is.
+// If a decrypt or decode attempt is successful, the resulting value is used
+// for message delivery. If unsuccessful the original input value is provided
+// as-is. An empty string is returned if no webhook URLs were specified.
 func (c Config) WebhookURL() string {
-	webhookURL, err := webhookurl.DecodeBase64(c.webhookURL)
+	if len(c.webhookURLs) == 0 {
+		return ""
+	}
+
+	return c.decodeWebhookURL(c.webhookURLs[0])
+}
+
+// WebhookURLs returns the processed form of each user-specified target
+// Microsoft Teams webhook URL. See WebhookURL for details.
+func (c Config) WebhookURLs() []string {
+	webhookURLs := make([]string, 0, len(c.webhookURLs))
+	for _, webhookURL := range c.webhookURLs {
+		webhookURLs = append(webhookURLs, c.decodeWebhookURL(webhookURL))
+	}
+
+	return webhookURLs
+}
+
+// ConfiguredWebhookURLs returns each user-specified target Microsoft Teams
+// webhook URL as provided (e.g., encrypted or base64 encoded). Unlike
+// WebhookURLs, the values are not decrypted or decoded and are suitable for
+// storing alongside a message for later delivery. See DecodeWebhookURL.
+func (c Config) ConfiguredWebhookURLs() []string {
+	webhookURLs := make([]string, len(c.webhookURLs))
+	copy(webhookURLs, c.webhookURLs)
+
+	return webhookURLs
+}
+
+// DecodeWebhookURL returns the processed form of the given webhook URL as
+// provided by ConfiguredWebhookURLs. See WebhookURL for details. An error is
+// returned if the webhook URL is encrypted and cannot be decrypted using
+// the user-specified key.
+func (c Config) DecodeWebhookURL(input string) (string, error) {
+	if webhookurl.IsEncrypted(input) {
+		if c.webhookURLKey == nil {
+			return "", fmt.Errorf("%w; use the key-file flag", ErrKeyRequired)
+		}
+
+		webhookURL, err := webhookurl.Decrypt(input, c.webhookURLKey)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(webhookURL), nil
+	}
+
+	return c.decodeWebhookURL(input), nil
+}
+
+// hasBase64WebhookURL indicates whether any user-specified webhook URL is
+// base64 encoded.
+func (c Config) hasBase64WebhookURL() bool {
+	for _, webhookURL := range c.webhookURLs {
+		if webhookurl.IsBase64URL(webhookURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeWebhookURL attempts to decrypt the given webhook URL (if encrypted)
+// or to decode it as one or more base64 strings. If unsuccessful the
+// original input value is returned as-is.
+func (c Config) decodeWebhookURL(input string) string {
+	if webhookurl.IsEncrypted(input) {
+		webhookURL, err := webhookurl.Decrypt(input, c.webhookURLKey)
+		if err != nil {
+			// Encrypted webhook URLs are validated separately.
+			return input
+		}
+
+		return strings.TrimSpace(webhookURL)
+	}
+
+	webhookURL, err := webhookurl.DecodeBase64(input)
 	if err != nil {
 		// If base64 decoding fails return the original value as-is.
-		return c.webhookURL
+		return input
 	}
 
 	return strings.TrimSpace(string(webhookURL))
@@ -0,0 +1,177 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrMissingIcinga2Variables indicates that Icinga 2 mode was requested, but
+// the expected Icinga 2 environment variables were not found.
+var ErrMissingIcinga2Variables = errors.New("required Icinga 2 environment variables not found")
+
+// icingaweb2URLEnvVar is the environment variable used by the notification
+// scripts bundled with Icinga 2 to provide the Icinga Web 2 base URL.
+const icingaweb2URLEnvVar string = "ICINGAWEB2URL"
+
+// defaultIcinga2Sender is the sender used for messages generated in Icinga 2
+// mode if a sender is not specified.
+const defaultIcinga2Sender string = "Icinga 2"
+
+// icinga2Env returns the value of the specified Icinga 2 environment
+// variable with leading and trailing whitespace removed.
+func icinga2Env(name string) string {
+	return strings.TrimSpace(os.Getenv(name))
+}
+
+// newIcinga2Notification creates a notification using the environment
+// variables set by an Icinga 2 NotificationCommand. Service variables are
+// used if the SERVICENAME environment variable is set, otherwise host
+// variables are used. Links to the host and service in Icinga Web 2 are
+// added if the Icinga Web 2 base URL is provided.
+func newIcinga2Notification(icingaweb2URL string) (notification, error) {
+	n := notification{
+		Type:        icinga2Env("NOTIFICATIONTYPE"),
+		HostName:    icinga2Env("HOSTNAME"),
+		HostAlias:   icinga2Env("HOSTDISPLAYNAME"),
+		HostAddress: icinga2Env("HOSTADDRESS"),
+		DateTime:    icinga2Env("LONGDATETIME"),
+		Author:      icinga2Env("NOTIFICATIONAUTHORNAME"),
+		Comment:     icinga2Env("NOTIFICATIONCOMMENT"),
+	}
+
+	for _, required := range []struct {
+		name  string
+		value string
+	}{
+		{name: "NOTIFICATIONTYPE", value: n.Type},
+		{name: "HOSTNAME", value: n.HostName},
+	} {
+		if required.value == "" {
+			return notification{}, fmt.Errorf(
+				"%w: %s is not set; is it set via the env attribute of the NotificationCommand?",
+				ErrMissingIcinga2Variables,
+				required.name,
+			)
+		}
+	}
+
+	if n.HostAddress == "" {
+		n.HostAddress = icinga2Env("HOSTADDRESS6")
+	}
+
+	serviceName := icinga2Env("SERVICENAME")
+
+	switch {
+	case serviceName != "":
+		n.ServiceDesc = icinga2Env("SERVICEDISPLAYNAME")
+		if n.ServiceDesc == "" {
+			n.ServiceDesc = serviceName
+		}
+		n.State = icinga2Env("SERVICESTATE")
+		n.Output = icinga2Env("SERVICEOUTPUT")
+
+	default:
+		n.State = icinga2Env("HOSTSTATE")
+		n.Output = icinga2Env("HOSTOUTPUT")
+	}
+
+	n.Links = icingaweb2Links(icingaweb2URL, n.HostName, serviceName)
+
+	return n, nil
+}
+
+// icingaweb2Links returns links to the given host and (if specified) service
+// using the Icinga Web 2 monitoring module URL format used by the
+// notification scripts bundled with Icinga 2. No links are returned if the
+// Icinga Web 2 base URL is not specified.
+func icingaweb2Links(baseURL string, hostName string, serviceName string) []TargetURL {
+	if baseURL == "" {
+		return nil
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil
+	}
+
+	links := make([]TargetURL, 0, 2)
+
+	if serviceName != "" {
+		serviceURL := *base
+		serviceURL.Path += "/monitoring/service/show"
+		serviceURL.RawQuery = url.Values{
+			"host":    []string{hostName},
+			"service": []string{serviceName},
+		}.Encode()
+
+		links = append(links, TargetURL{URL: serviceURL, Description: "View service"})
+	}
+
+	hostURL := *base
+	hostURL.Path += "/monitoring/host/show"
+	hostURL.RawQuery = url.Values{
+		"host": []string{hostName},
+	}.Encode()
+
+	links = append(links, TargetURL{URL: hostURL, Description: "View host"})
+
+	return links
+}
+
+// validateIcingaweb2URL asserts that the given Icinga Web 2 base URL (if
+// specified) is a valid absolute HTTP or HTTPS URL.
+func validateIcingaweb2URL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	switch {
+	case err != nil:
+		return fmt.Errorf("invalid Icinga Web 2 URL: %w", err)
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("invalid Icinga Web 2 URL %q: scheme must be http or https", rawURL)
+	case u.Host == "":
+		return fmt.Errorf("invalid Icinga Web 2 URL %q: host not specified", rawURL)
+	}
+
+	return nil
+}
+
+// handleIcinga2Mode populates message details from Icinga 2 environment
+// variables if Icinga 2 mode was requested. The Icinga Web 2 base URL is
+// obtained from the ICINGAWEB2URL environment variable if not otherwise
+// specified. Requesting more than one notification mode is reported by
+// Validate.
+func (c *Config) handleIcinga2Mode() error {
+	if !c.Icinga2Mode || c.conflictingModes() {
+		return nil
+	}
+
+	if c.Icingaweb2URL == "" {
+		c.Icingaweb2URL = icinga2Env(icingaweb2URLEnvVar)
+	}
+
+	if err := validateIcingaweb2URL(c.Icingaweb2URL); err != nil {
+		return err
+	}
+
+	n, err := newIcinga2Notification(c.Icingaweb2URL)
+	if err != nil {
+		return err
+	}
+
+	c.applyNotification(n, defaultIcinga2Sender)
+
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// icinga2TestVars are the names of the Icinga 2 environment variables
+// cleared before each test. HOSTNAME in particular is commonly set by the
+// shell.
+var icinga2TestVars = []string{
+	"NOTIFICATIONTYPE", "HOSTNAME", "HOSTDISPLAYNAME", "HOSTADDRESS",
+	"HOSTADDRESS6", "LONGDATETIME", "NOTIFICATIONAUTHORNAME",
+	"NOTIFICATIONCOMMENT", "SERVICENAME", "SERVICEDISPLAYNAME",
+	"SERVICESTATE", "SERVICEOUTPUT", "HOSTSTATE", "HOSTOUTPUT",
+	icingaweb2URLEnvVar,
+}
+
+// setIcinga2Vars sets the given Icinga 2 environment variables for the
+// duration of the test, clearing all other variables.
+func setIcinga2Vars(t *testing.T, vars map[string]string) {
+	t.Helper()
+
+	for _, name := range icinga2TestVars {
+		t.Setenv(name, "")
+	}
+
+	for name, value := range vars {
+		t.Setenv(name, value)
+	}
+}
+
+func TestNewIcinga2Notification(t *testing.T) {
+	tests := map[string]struct {
+		vars          map[string]string
+		icingaweb2URL string
+		expected      notification
+	}{
+		"service notification": {
+			vars: map[string]string{
+				"NOTIFICATIONTYPE":   "PROBLEM",
+				"HOSTNAME":           "web01",
+				"HOSTDISPLAYNAME":    "Web server",
+				"HOSTADDRESS":        "192.0.2.10",
+				"SERVICENAME":        "http",
+				"SERVICEDISPLAYNAME": "HTTP",
+				"SERVICESTATE":       "CRITICAL",
+				"SERVICEOUTPUT":      "connection refused",
+				"HOSTSTATE":          "UP",
+			},
+			expected: notification{
+				Type:        "PROBLEM",
+				HostName:    "web01",
+				HostAlias:   "Web server",
+				HostAddress: "192.0.2.10",
+				ServiceDesc: "HTTP",
+				State:       "CRITICAL",
+				Output:      "connection refused",
+			},
+		},
+		"service name used without display name": {
+			vars: map[string]string{
+				"NOTIFICATIONTYPE": "PROBLEM",
+				"HOSTNAME":         "web01",
+				"SERVICENAME":      "http",
+				"SERVICESTATE":     "WARNING",
+			},
+			expected: notification{
+				Type:        "PROBLEM",
+				HostName:    "web01",
+				ServiceDesc: "http",
+				State:       "WARNING",
+			},
+		},
+		"host notification with IPv6 address": {
+			vars: map[string]string{
+				"NOTIFICATIONTYPE": "RECOVERY",
+				"HOSTNAME":         "web01",
+				"HOSTADDRESS6":     "2001:db8::10",
+				"HOSTSTATE":        "UP",
+				"HOSTOUTPUT":       "PING OK",
+				"SERVICESTATE":     "CRITICAL",
+			},
+			expected: notification{
+				Type:        "RECOVERY",
+				HostName:    "web01",
+				HostAddress: "2001:db8::10",
+				State:       "UP",
+				Output:      "PING OK",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setIcinga2Vars(t, tt.vars)
+
+			got, err := newIcinga2Notification(tt.icingaweb2URL)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %+v; expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewIcinga2NotificationMissingVariables(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing notification type": {"HOSTNAME": "web01"},
+		"missing host name":         {"NOTIFICATIONTYPE": "PROBLEM"},
+	}
+
+	for name, vars := range tests {
+		t.Run(name, func(t *testing.T) {
+			setIcinga2Vars(t, vars)
+
+			if _, err := newIcinga2Notification(""); !errors.Is(err, ErrMissingIcinga2Variables) {
+				t.Fatalf("got %v; expected error %q", err, ErrMissingIcinga2Variables)
+			}
+		})
+	}
+}
+
+func TestIcingaweb2Links(t *testing.T) {
+	tests := map[string]struct {
+		baseURL     string
+		serviceName string
+		expected    []string
+	}{
+		"service": {
+			baseURL:     "https://icinga.example.com/icingaweb2/",
+			serviceName: "disk /var",
+			expected: []string{
+				"https://icinga.example.com/icingaweb2/monitoring/service/show?host=web01&service=disk+%2Fvar",
+				"https://icinga.example.com/icingaweb2/monitoring/host/show?host=web01",
+			},
+		},
+		"host": {
+			baseURL: "https://icinga.example.com/icingaweb2",
+			expected: []string{
+				"https://icinga.example.com/icingaweb2/monitoring/host/show?host=web01",
+			},
+		},
+		"base URL not specified": {
+			serviceName: "http",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			links := icingaweb2Links(tt.baseURL, "web01", tt.serviceName)
+
+			var got []string
+			for _, link := range links {
+				got = append(got, link.URL.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateIcingaweb2URL(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		expectErr bool
+	}{
+		"https":          {input: "https://icinga.example.com/icingaweb2"},
+		"http":           {input: "http://icinga.example.com"},
+		"not specified":  {input: ""},
+		"missing scheme": {input: "icinga.example.com/icingaweb2", expectErr: true},
+		"other scheme":   {input: "ftp://icinga.example.com", expectErr: true},
+		"missing host":   {input: "https:///icingaweb2", expectErr: true},
+		"invalid":        {input: "https://icinga example.com", expectErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateIcingaweb2URL(tt.input)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Errorf("got nil; expected error for %q", tt.input)
+			case !tt.expectErr && err != nil:
+				t.Errorf("got %v; expected no error", err)
+			}
+		})
+	}
+}
+
+func TestHandleIcinga2Mode(t *testing.T) {
+	setIcinga2Vars(t, map[string]string{
+		"NOTIFICATIONTYPE":  "PROBLEM",
+		"HOSTNAME":          "web01",
+		"HOSTSTATE":         "DOWN",
+		"HOSTOUTPUT":        "PING CRITICAL",
+		icingaweb2URLEnvVar: "https://icinga.example.com/icingaweb2",
+	})
+
+	c := Config{Icinga2Mode: true}
+
+	if err := c.handleIcinga2Mode(); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if c.Icingaweb2URL != "https://icinga.example.com/icingaweb2" {
+		t.Errorf("got %q; expected Icinga Web 2 URL from environment", c.Icingaweb2URL)
+	}
+
+	if c.MessageTitle != "PROBLEM: web01 is DOWN" {
+		t.Errorf("got title %q; expected title from environment", c.MessageTitle)
+	}
+
+	if c.Severity != SeverityCritical {
+		t.Errorf("got severity %q; expected %q", c.Severity, SeverityCritical)
+	}
+
+	if c.Sender != defaultIcinga2Sender {
+		t.Errorf("got sender %q; expected %q", c.Sender, defaultIcinga2Sender)
+	}
+
+	if len(c.TargetURLs) != 1 || c.TargetURLs[0].Description != "View host" {
+		t.Errorf("got %v; expected a link to the host", c.TargetURLs)
+	}
+}
+
+func TestHandleIcinga2ModeInvalidURL(t *testing.T) {
+	setIcinga2Vars(t, map[string]string{
+		"NOTIFICATIONTYPE": "PROBLEM",
+		"HOSTNAME":         "web01",
+	})
+
+	c := Config{Icinga2Mode: true, Icingaweb2URL: "icinga.example.com"}
+
+	if err := c.handleIcinga2Mode(); err == nil {
+		t.Fatalf("got nil; expected error for invalid Icinga Web 2 URL")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinFileName is the conventional file name used to indicate that input
+// should be read from standard input instead of a file.
+const stdinFileName string = "-"
+
+// ErrConflictingMessageSources indicates that the user specified more than
+// one source for the message to submit.
+var ErrConflictingMessageSources = errors.New("conflicting message sources specified")
+
+// supportedInputFormats returns the list of supported input formats.
+func supportedInputFormats() []string {
+	return []string{
+		InputFormatAlertmanager,
+		InputFormatGrafana,
+	}
+}
+
+// handleMessageInput populates the MessageText field using the
+// user-specified message file or standard input, the Payload field using
+// the user-specified payload file or the Input field using the message file
+// or standard input if an input format was specified. If none were
+// specified the MessageText field is left as-is. An error is returned if
+// more than one message source was specified or if reading the message
+// fails.
+func (c *Config) handleMessageInput() error {
+	var sources int
+
+	// Notifications in a specific input format are read from standard input
+	// unless a message file is specified.
+	if c.InputFormat != "" && c.MessageFile == "" && !c.MessageFromStdin {
+		sources++
+	}
+	if c.MessageText != "" {
+		sources++
+	}
+	if c.MessageFile != "" {
+		sources++
+	}
+	if c.MessageFromStdin {
+		sources++
+	}
+	if c.PayloadFile != "" {
+		sources++
+	}
+	if c.Template != "" {
+		sources++
+	}
+	if c.TemplateFile != "" {
+		sources++
+	}
+
+	if sources > 1 {
+		return fmt.Errorf(
+			"%w: only one of message, message-file, stdin, payload-file, template or template-file flags may be used",
+			ErrConflictingMessageSources,
+		)
+	}
+
+	switch {
+	case c.InputFormat != "":
+		filename := c.MessageFile
+		if filename == "" {
+			filename = stdinFileName
+		}
+
+		data, err := readInputFile(filename)
+		if err != nil {
+			return fmt.Errorf("failed to read %s notification: %w", c.InputFormat, err)
+		}
+		c.Input = data
+
+	case c.MessageFromStdin, c.MessageFile == stdinFileName:
+		text, err := readMessage(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read message from standard input: %w", err)
+		}
+		c.MessageText = text
+		c.valueSources["message"] = sourceStdin
+
+	case c.MessageFile != "":
+		data, err := readInputFile(c.MessageFile)
+		if err != nil {
+			return fmt.Errorf("failed to read message file: %w", err)
+		}
+
+		text, err := readMessage(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to read message file %s: %w", c.MessageFile, err)
+		}
+		c.MessageText = text
+		c.valueSources["message"] = sourceFile
+
+	case c.PayloadFile != "":
+		data, err := readInputFile(c.PayloadFile)
+		if err != nil {
+			return fmt.Errorf("failed to read payload file: %w", err)
+		}
+		c.Payload = data
+	}
+
+	return nil
+}
+
+// readInputFile reads all content from the specified file. If the filename
+// is "-" content is read from standard input instead.
+func readInputFile(filename string) ([]byte, error) {
+	if filename == stdinFileName {
+		return io.ReadAll(os.Stdin)
+	}
+
+	// #nosec G304 -- file path is intentionally provided by the user
+	return os.ReadFile(filename)
+}
+
+// readMessage reads all content from the given reader for use as message
+// text. Trailing newlines (commonly emitted by commands whose output is
+// piped to this application) are removed. Content consisting only of
+// whitespace is treated as empty.
+func readMessage(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+
+	return text, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+// keyCredentialName is the name of the systemd credential containing the key
+// used to decrypt encrypted webhook URLs.
+const keyCredentialName string = "send2teams-key"
+
+// ErrKeyRequired indicates that an encrypted webhook URL was specified
+// without a key to decrypt it.
+var ErrKeyRequired = errors.New("webhook URL is encrypted but a key file was not specified")
+
+// handleKeyFile loads the key used to decrypt encrypted webhook URLs from
+// the user-specified key file. If a key file is not specified, the systemd
+// credential named send2teams-key is used if present. An error is returned
+// if reading the file fails or if the file does not contain a valid key.
+func (c *Config) handleKeyFile() error {
+	filename := c.KeyFile
+
+	if filename == "" {
+		credentialsDir := os.Getenv(credentialsDirectoryEnvVar)
+		if credentialsDir == "" {
+			return nil
+		}
+
+		credential := filepath.Join(credentialsDir, keyCredentialName)
+
+		_, err := os.Stat(credential)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to access %s credential: %w", keyCredentialName, err)
+		}
+
+		filename = credential
+	}
+
+	data, err := readSecretFile(filename, "webhook URL key file", c.AllowInsecureURLFile)
+	if err != nil {
+		return err
+	}
+
+	key, err := webhookurl.ParseKey(data)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook URL key file %q: %w", filename, err)
+	}
+
+	c.webhookURLKey = key
+
+	return nil
+}
+
+// hasEncryptedWebhookURL indicates whether any user-specified webhook URL is
+// encrypted.
+func (c Config) hasEncryptedWebhookURL() bool {
+	for _, webhookURL := range c.webhookURLs {
+		if webhookurl.IsEncrypted(webhookURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateEncryptedWebhookURLs asserts that each encrypted user-specified
+// webhook URL can be decrypted using the user-specified key.
+func (c Config) validateEncryptedWebhookURLs() error {
+	for i, webhookURL := range c.webhookURLs {
+		if !webhookurl.IsEncrypted(webhookURL) {
+			continue
+		}
+
+		if c.webhookURLKey == nil {
+			return fmt.Errorf(
+				"%w for webhook URL %d of %d; use the key-file flag",
+				ErrKeyRequired,
+				i+1,
+				len(c.webhookURLs),
+			)
+		}
+
+		if _, err := webhookurl.Decrypt(webhookURL, c.webhookURLKey); err != nil {
+			return fmt.Errorf(
+				"invalid encrypted webhook URL %d of %d: %w",
+				i+1,
+				len(c.webhookURLs),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrMissingNagiosMacros indicates that Nagios mode was requested, but the
+// expected Nagios environment macros were not found.
+var ErrMissingNagiosMacros = errors.New("required Nagios environment macros not found")
+
+// nagiosEnvVarPrefix is the prefix used by Nagios for all environment macros
+// when the enable_environment_macros setting is enabled.
+const nagiosEnvVarPrefix string = "NAGIOS_"
+
+// defaultNagiosSender is the sender used for messages generated in Nagios
+// mode if a sender is not specified.
+const defaultNagiosSender string = "Nagios"
+
+// notification represents the details of a host or service notification
+// from a monitoring system such as Nagios.
+type notification struct {
+	// Type is the notification type (e.g., PROBLEM, RECOVERY).
+	Type string
+
+	// HostName is the short name of the host.
+	HostName string
+
+	// HostAlias is the long name or description of the host.
+	HostAlias string
+
+	// HostAddress is the address of the host.
+	HostAddress string
+
+	// ServiceDesc is the description of the service. This value is empty
+	// for host notifications.
+	ServiceDesc string
+
+	// State is the current host or service state (e.g., DOWN, CRITICAL).
+	State string
+
+	// Output is the first line of text output from the last check.
+	Output string
+
+	// LongOutput is the full text output (aside from the first line) from
+	// the last check.
+	LongOutput string
+
+	// DateTime is the human-readable date and time of the notification.
+	DateTime string
+
+	// Duration is the amount of time that the host or service has been in
+	// the current state.
+	Duration string
+
+	// Attempt is the current check attempt number.
+	Attempt string
+
+	// MaxAttempts is the maximum number of check attempts.
+	MaxAttempts string
+
+	// CheckCommand is the name of the command (along with any arguments)
+	// used to check the host or service.
+	CheckCommand string
+
+	// Author is the name of the user who authored an acknowledgement,
+	// downtime or custom notification.
+	Author string
+
+	// Comment is the comment entered by the author of an acknowledgement,
+	// downtime or custom notification.
+	Comment string
+
+	// NotesURL is an optional URL providing more information about the host
+	// or service.
+	NotesURL string
+
+	// ActionURL is an optional URL providing actions for the host or
+	// service.
+	ActionURL string
+
+	// Links is an optional collection of additional links for the host or
+	// service (e.g., to a monitoring web interface).
+	Links []TargetURL
+}
+
+// nagiosEnv returns the value of the specified Nagios environment macro with
+// leading and trailing whitespace removed.
+func nagiosEnv(name string) string {
+	return strings.TrimSpace(os.Getenv(nagiosEnvVarPrefix + name))
+}
+
+// nagiosLongOutput converts escaped newlines in long plugin output provided
+// via Nagios environment macros to actual newlines.
+func nagiosLongOutput(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
+
+// newNagiosNotification creates a notification using Nagios environment
+// macros. Service macros are used if the NAGIOS_SERVICEDESC environment
+// macro is set, otherwise host macros are used.
+func newNagiosNotification() (notification, error) {
+	n := notification{
+		Type:        nagiosEnv("NOTIFICATIONTYPE"),
+		HostName:    nagiosEnv("HOSTNAME"),
+		HostAlias:   nagiosEnv("HOSTALIAS"),
+		HostAddress: nagiosEnv("HOSTADDRESS"),
+		ServiceDesc: nagiosEnv("SERVICEDESC"),
+		DateTime:    nagiosEnv("LONGDATETIME"),
+		Author:      nagiosEnv("NOTIFICATIONAUTHOR"),
+		Comment:     nagiosEnv("NOTIFICATIONCOMMENT"),
+	}
+
+	if n.HostName == "" {
+		return notification{}, fmt.Errorf(
+			"%w: %sHOSTNAME is not set; is enable_environment_macros enabled?",
+			ErrMissingNagiosMacros,
+			nagiosEnvVarPrefix,
+		)
+	}
+
+	switch {
+	case n.ServiceDesc != "":
+		n.State = nagiosEnv("SERVICESTATE")
+		n.Output = nagiosEnv("SERVICEOUTPUT")
+		n.LongOutput = nagiosLongOutput(nagiosEnv("LONGSERVICEOUTPUT"))
+		n.Duration = nagiosEnv("SERVICEDURATION")
+		n.Attempt = nagiosEnv("SERVICEATTEMPT")
+		n.MaxAttempts = nagiosEnv("MAXSERVICEATTEMPTS")
+		n.CheckCommand = nagiosEnv("SERVICECHECKCOMMAND")
+		n.NotesURL = nagiosEnv("SERVICENOTESURL")
+		n.ActionURL = nagiosEnv("SERVICEACTIONURL")
+
+	default:
+		n.State = nagiosEnv("HOSTSTATE")
+		n.Output = nagiosEnv("HOSTOUTPUT")
+		n.LongOutput = nagiosLongOutput(nagiosEnv("LONGHOSTOUTPUT"))
+		n.Duration = nagiosEnv("HOSTDURATION")
+		n.Attempt = nagiosEnv("HOSTATTEMPT")
+		n.MaxAttempts = nagiosEnv("MAXHOSTATTEMPTS")
+		n.CheckCommand = nagiosEnv("HOSTCHECKCOMMAND")
+		n.NotesURL = nagiosEnv("HOSTNOTESURL")
+		n.ActionURL = nagiosEnv("HOSTACTIONURL")
+	}
+
+	return n, nil
+}
+
+// isService indicates whether the notification is for a service (as opposed
+// to a host).
+func (n notification) isService() bool {
+	return n.ServiceDesc != ""
+}
+
+// title returns a title for the notification emulating the subject line of
+// the default Nagios notification commands.
+func (n notification) title() string {
+	notificationType := n.Type
+	if notificationType == "" {
+		notificationType = "NOTIFICATION"
+	}
+
+	switch {
+	case n.isService():
+		return fmt.Sprintf(
+			"%s: %s/%s is %s",
+			notificationType,
+			n.HostName,
+			n.ServiceDesc,
+			n.State,
+		)
+	default:
+		return fmt.Sprintf(
+			"%s: %s is %s",
+			notificationType,
+			n.HostName,
+			n.State,
+		)
+	}
+}
+
+// text returns the message text for the notification composed of the check
+// output and (if present) long check output.
+func (n notification) text() string {
+	text := n.Output
+	if n.LongOutput != "" {
+		text += "\n\n" + n.LongOutput
+	}
+
+	if strings.TrimSpace(text) == "" {
+		text = "(no output)"
+	}
+
+	return text
+}
+
+// facts returns a collection of key details for the notification. Details
+// without a value are omitted.
+func (n notification) facts() []Fact {
+	attempt := n.Attempt
+	if attempt != "" && n.MaxAttempts != "" {
+		attempt = n.Attempt + "/" + n.MaxAttempts
+	}
+
+	host := n.HostName
+	if n.HostAlias != "" && n.HostAlias != n.HostName {
+		host = fmt.Sprintf("%s (%s)", n.HostName, n.HostAlias)
+	}
+
+	candidates := []Fact{
+		{Title: "Notification", Value: n.Type},
+		{Title: "Host", Value: host},
+		{Title: "Address", Value: n.HostAddress},
+		{Title: "Service", Value: n.ServiceDesc},
+		{Title: "State", Value: n.State},
+		{Title: "Duration", Value: n.Duration},
+		{Title: "Attempt", Value: attempt},
+		{Title: "Check command", Value: n.CheckCommand},
+		{Title: "Date/Time", Value: n.DateTime},
+		{Title: "Author", Value: n.Author},
+		{Title: "Comment", Value: n.Comment},
+	}
+
+	facts := make([]Fact, 0, len(candidates))
+	for _, fact := range candidates {
+		if fact.Value != "" {
+			facts = append(facts, fact)
+		}
+	}
+
+	return facts
+}
+
+// severity returns the message severity for the notification based on the
+// notification type and host or service state.
+func (n notification) severity() string {
+	switch strings.ToUpper(n.Type) {
+	case "RECOVERY":
+		return SeverityOK
+	case "ACKNOWLEDGEMENT", "CUSTOM",
+		"FLAPPINGSTART", "FLAPPINGSTOP", "FLAPPINGEND", "FLAPPINGDISABLED",
+		"DOWNTIMESTART", "DOWNTIMEEND", "DOWNTIMECANCELLED", "DOWNTIMEREMOVED":
+		return SeverityInfo
+	}
+
+	switch strings.ToUpper(n.State) {
+	case "OK", "UP":
+		return SeverityOK
+	case "WARNING":
+		return SeverityWarning
+	case "CRITICAL", "DOWN", "UNREACHABLE":
+		return SeverityCritical
+	default:
+		return SeverityUnknown
+	}
+}
+
+// targetURLs returns target URLs for the notes and action URLs of the host
+// or service (if set) followed by any additional links. Invalid URLs are
+// skipped.
+func (n notification) targetURLs() []TargetURL {
+	candidates := []struct {
+		rawURL string
+		desc   string
+	}{
+		{rawURL: n.NotesURL, desc: "Notes"},
+		{rawURL: n.ActionURL, desc: "Actions"},
+	}
+
+	targetURLs := make([]TargetURL, 0, len(candidates)+len(n.Links))
+	for _, candidate := range candidates {
+		if candidate.rawURL == "" {
+			continue
+		}
+
+		u, err := url.Parse(candidate.rawURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+
+		targetURLs = append(targetURLs, TargetURL{URL: *u, Description: candidate.desc})
+	}
+
+	return append(targetURLs, n.Links...)
+}
+
+// notificationModes returns the names of the requested modes which generate
+// messages from the notifications of a monitoring system.
+func (c Config) notificationModes() []string {
+	modes := make([]string, 0, 3)
+
+	for _, mode := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "Nagios", enabled: c.NagiosMode},
+		{name: "Icinga 2", enabled: c.Icinga2Mode},
+		{name: "Zabbix", enabled: c.ZabbixMode},
+	} {
+		if mode.enabled {
+			modes = append(modes, mode.name)
+		}
+	}
+
+	return modes
+}
+
+// conflictingModes indicates whether more than one notification mode was
+// requested.
+func (c Config) conflictingModes() bool {
+	return len(c.notificationModes()) > 1
+}
+
+// applyNotification uses the given notification details to populate the
+// message title, text, facts, severity and target URLs. Values specified by
+// the user via flag or environment variable are not overridden.
+func (c *Config) applyNotification(n notification, sender string) {
+	if !c.setByUser("title") {
+		c.MessageTitle = n.title()
+	}
+
+	if !c.setByUser("message") {
+		c.MessageText = n.text()
+	}
+
+	if !c.setByUser("sender") && c.Sender == "" {
+		c.Sender = sender
+	}
+
+	if !c.setByUser("target-url") {
+		c.TargetURLs = append(c.TargetURLs, n.targetURLs()...)
+	}
+
+	if !c.setByUser("severity") {
+		c.Severity = n.severity()
+	}
+
+	// Facts specified by the user are listed after the notification facts.
+	c.Facts = append(n.facts(), c.Facts...)
+}
+
+// handleNagiosMode populates message details from Nagios environment macros
+// if Nagios mode was requested. Requesting more than one notification mode
+// is reported by Validate.
+func (c *Config) handleNagiosMode() error {
+	if !c.NagiosMode || c.conflictingModes() {
+		return nil
+	}
+
+	n, err := newNagiosNotification()
+	if err != nil {
+		return err
+	}
+
+	c.applyNotification(n, defaultNagiosSender)
+
+	return nil
+}
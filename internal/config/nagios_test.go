@@ -0,0 +1,281 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// nagiosTestMacros are the names (without prefix) of the Nagios environment
+// macros cleared before each test.
+var nagiosTestMacros = []string{
+	"NOTIFICATIONTYPE", "HOSTNAME", "HOSTALIAS", "HOSTADDRESS", "SERVICEDESC",
+	"LONGDATETIME", "NOTIFICATIONAUTHOR", "NOTIFICATIONCOMMENT",
+	"SERVICESTATE", "SERVICEOUTPUT", "LONGSERVICEOUTPUT", "SERVICEDURATION",
+	"SERVICEATTEMPT", "MAXSERVICEATTEMPTS", "SERVICECHECKCOMMAND",
+	"SERVICENOTESURL", "SERVICEACTIONURL",
+	"HOSTSTATE", "HOSTOUTPUT", "LONGHOSTOUTPUT", "HOSTDURATION",
+	"HOSTATTEMPT", "MAXHOSTATTEMPTS", "HOSTCHECKCOMMAND",
+	"HOSTNOTESURL", "HOSTACTIONURL",
+}
+
+// setNagiosMacros sets the given Nagios environment macros for the duration
+// of the test, clearing all other macros.
+func setNagiosMacros(t *testing.T, macros map[string]string) {
+	t.Helper()
+
+	for _, name := range nagiosTestMacros {
+		t.Setenv(nagiosEnvVarPrefix+name, "")
+	}
+
+	for name, value := range macros {
+		t.Setenv(nagiosEnvVarPrefix+name, value)
+	}
+}
+
+func TestNewNagiosNotification(t *testing.T) {
+	tests := map[string]struct {
+		macros   map[string]string
+		expected notification
+	}{
+		"service notification": {
+			macros: map[string]string{
+				"NOTIFICATIONTYPE":   "PROBLEM",
+				"HOSTNAME":           "web01",
+				"HOSTALIAS":          "Web server",
+				"SERVICEDESC":        "HTTP",
+				"SERVICESTATE":       "CRITICAL",
+				"SERVICEOUTPUT":      " connection refused ",
+				"LONGSERVICEOUTPUT":  `line 1\nline 2`,
+				"SERVICEATTEMPT":     "3",
+				"MAXSERVICEATTEMPTS": "3",
+				"HOSTSTATE":          "UP",
+				"HOSTOUTPUT":         "host output is ignored",
+			},
+			expected: notification{
+				Type:        "PROBLEM",
+				HostName:    "web01",
+				HostAlias:   "Web server",
+				ServiceDesc: "HTTP",
+				State:       "CRITICAL",
+				Output:      "connection refused",
+				LongOutput:  "line 1\nline 2",
+				Attempt:     "3",
+				MaxAttempts: "3",
+			},
+		},
+		"host notification": {
+			macros: map[string]string{
+				"NOTIFICATIONTYPE": "RECOVERY",
+				"HOSTNAME":         "web01",
+				"HOSTADDRESS":      "192.0.2.10",
+				"HOSTSTATE":        "UP",
+				"HOSTOUTPUT":       "PING OK",
+				"HOSTNOTESURL":     "https://wiki.example.com/web01",
+				"SERVICESTATE":     "CRITICAL",
+			},
+			expected: notification{
+				Type:        "RECOVERY",
+				HostName:    "web01",
+				HostAddress: "192.0.2.10",
+				State:       "UP",
+				Output:      "PING OK",
+				NotesURL:    "https://wiki.example.com/web01",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setNagiosMacros(t, tt.macros)
+
+			got, err := newNagiosNotification()
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %+v; expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewNagiosNotificationMissingMacros(t *testing.T) {
+	setNagiosMacros(t, map[string]string{"SERVICEDESC": "HTTP"})
+
+	if _, err := newNagiosNotification(); !errors.Is(err, ErrMissingNagiosMacros) {
+		t.Fatalf("got %v; expected error %q", err, ErrMissingNagiosMacros)
+	}
+}
+
+func TestNotificationTitleAndText(t *testing.T) {
+	tests := map[string]struct {
+		n             notification
+		expectedTitle string
+		expectedText  string
+	}{
+		"service": {
+			n:             notification{Type: "PROBLEM", HostName: "web01", ServiceDesc: "HTTP", State: "CRITICAL", Output: "refused", LongOutput: "details"},
+			expectedTitle: "PROBLEM: web01/HTTP is CRITICAL",
+			expectedText:  "refused\n\ndetails",
+		},
+		"host": {
+			n:             notification{Type: "RECOVERY", HostName: "web01", State: "UP", Output: "PING OK"},
+			expectedTitle: "RECOVERY: web01 is UP",
+			expectedText:  "PING OK",
+		},
+		"missing type and output": {
+			n:             notification{HostName: "web01", State: "DOWN"},
+			expectedTitle: "NOTIFICATION: web01 is DOWN",
+			expectedText:  "(no output)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.n.title(); got != tt.expectedTitle {
+				t.Errorf("got title %q; expected %q", got, tt.expectedTitle)
+			}
+
+			if got := tt.n.text(); got != tt.expectedText {
+				t.Errorf("got text %q; expected %q", got, tt.expectedText)
+			}
+		})
+	}
+}
+
+func TestNotificationSeverity(t *testing.T) {
+	tests := map[string]struct {
+		notificationType string
+		state            string
+		expected         string
+	}{
+		"service critical":       {notificationType: "PROBLEM", state: "CRITICAL", expected: SeverityCritical},
+		"service warning":        {notificationType: "PROBLEM", state: "WARNING", expected: SeverityWarning},
+		"service unknown":        {notificationType: "PROBLEM", state: "UNKNOWN", expected: SeverityUnknown},
+		"host down":              {notificationType: "PROBLEM", state: "DOWN", expected: SeverityCritical},
+		"host unreachable":       {notificationType: "PROBLEM", state: "UNREACHABLE", expected: SeverityCritical},
+		"recovery":               {notificationType: "RECOVERY", state: "OK", expected: SeverityOK},
+		"recovery type wins":     {notificationType: "recovery", state: "CRITICAL", expected: SeverityOK},
+		"acknowledgement":        {notificationType: "ACKNOWLEDGEMENT", state: "CRITICAL", expected: SeverityInfo},
+		"downtime start":         {notificationType: "DOWNTIMESTART", state: "DOWN", expected: SeverityInfo},
+		"flapping start":         {notificationType: "FLAPPINGSTART", state: "WARNING", expected: SeverityInfo},
+		"lowercase state":        {notificationType: "PROBLEM", state: "warning", expected: SeverityWarning},
+		"missing type and state": {expected: SeverityUnknown},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := notification{Type: tt.notificationType, State: tt.state}
+			if got := n.severity(); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotificationFacts(t *testing.T) {
+	n := notification{
+		Type:        "PROBLEM",
+		HostName:    "web01",
+		HostAlias:   "Web server",
+		ServiceDesc: "HTTP",
+		State:       "CRITICAL",
+		Attempt:     "1",
+		MaxAttempts: "3",
+	}
+
+	expected := []Fact{
+		{Title: "Notification", Value: "PROBLEM"},
+		{Title: "Host", Value: "web01 (Web server)"},
+		{Title: "Service", Value: "HTTP"},
+		{Title: "State", Value: "CRITICAL"},
+		{Title: "Attempt", Value: "1/3"},
+	}
+
+	if got := n.facts(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; expected %v", got, expected)
+	}
+}
+
+func TestNotificationTargetURLs(t *testing.T) {
+	n := notification{
+		NotesURL:  "https://wiki.example.com/web01",
+		ActionURL: "not a URL",
+	}
+
+	got := n.targetURLs()
+	if len(got) != 1 {
+		t.Fatalf("got %v; expected invalid action URL to be skipped", got)
+	}
+
+	if got[0].Description != "Notes" || got[0].URL.String() != n.NotesURL {
+		t.Errorf("got %v; expected notes URL %q", got[0], n.NotesURL)
+	}
+}
+
+func TestHandleNagiosMode(t *testing.T) {
+	setNagiosMacros(t, map[string]string{
+		"NOTIFICATIONTYPE": "PROBLEM",
+		"HOSTNAME":         "web01",
+		"SERVICEDESC":      "HTTP",
+		"SERVICESTATE":     "WARNING",
+		"SERVICEOUTPUT":    "slow response",
+	})
+
+	c := Config{
+		NagiosMode:  true,
+		MessageText: "user message",
+		Severity:    SeverityCritical,
+		valueSources: map[string]valueSource{
+			"message":  sourceEnv,
+			"severity": sourceConfigFile,
+		},
+	}
+
+	if err := c.handleNagiosMode(); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if c.MessageTitle != "PROBLEM: web01/HTTP is WARNING" {
+		t.Errorf("got title %q; expected title from macros", c.MessageTitle)
+	}
+
+	if c.MessageText != "user message" {
+		t.Errorf("got message %q; expected message set by user to be retained", c.MessageText)
+	}
+
+	// Settings from the configuration file are overridden by the
+	// notification.
+	if c.Severity != SeverityWarning {
+		t.Errorf("got severity %q; expected %q", c.Severity, SeverityWarning)
+	}
+
+	if c.Sender != defaultNagiosSender {
+		t.Errorf("got sender %q; expected %q", c.Sender, defaultNagiosSender)
+	}
+}
+
+func TestConflictingModes(t *testing.T) {
+	setNagiosMacros(t, nil)
+
+	c := Config{NagiosMode: true, ZabbixMode: true}
+
+	if !c.conflictingModes() {
+		t.Fatalf("got %v; expected conflicting modes", c.notificationModes())
+	}
+
+	// Conflicting modes are reported by Validate rather than by the
+	// notification mode handlers.
+	if err := c.handleNagiosMode(); err != nil {
+		t.Errorf("got %v; expected no error", err)
+	}
+}
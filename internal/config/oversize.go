@@ -0,0 +1,33 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+// Supported policies used to handle messages which exceed the size limit
+// enforced by Microsoft Teams.
+const (
+	// OversizeFail indicates that an oversized message is not submitted and
+	// an error is reported instead.
+	OversizeFail string = "fail"
+
+	// OversizeTruncate indicates that the body of an oversized message is
+	// truncated to fit within the size limit.
+	OversizeTruncate string = "truncate"
+
+	// OversizeSplit indicates that the body of an oversized message is split
+	// across multiple messages submitted in sequence.
+	OversizeSplit string = "split"
+)
+
+// supportedOversizePolicies returns the list of supported oversize policies.
+func supportedOversizePolicies() []string {
+	return []string{
+		OversizeFail,
+		OversizeTruncate,
+		OversizeSplit,
+	}
+}
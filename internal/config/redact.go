@@ -0,0 +1,65 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"github.com/atc0005/send2teams/internal/webhookurl"
+)
+
+// Redact returns the given text with webhook URL secrets masked in each
+// embedded URL unless the user requested that secrets be shown. This is
+// intended for log, verbose and error output.
+func (c Config) Redact(text string) string {
+	if c.ShowSecrets {
+		return text
+	}
+
+	return webhookurl.RedactText(text)
+}
+
+// RedactError returns an error whose message has webhook URL secrets masked
+// unless the user requested that secrets be shown. The given error remains
+// available via errors.Is and errors.As.
+func (c Config) RedactError(err error) error {
+	if c.ShowSecrets {
+		return err
+	}
+
+	return webhookurl.RedactError(err)
+}
+
+// RedactedWebhookURLs returns the processed form of each user-specified
+// webhook URL with secrets masked unless the user requested that secrets be
+// shown. See WebhookURLs for details.
+func (c Config) RedactedWebhookURLs() []string {
+	webhookURLs := c.WebhookURLs()
+	if c.ShowSecrets {
+		return webhookURLs
+	}
+
+	for i, webhookURL := range webhookURLs {
+		webhookURLs[i] = webhookurl.Redact(webhookURL)
+	}
+
+	return webhookURLs
+}
+
+// redactedRawWebhookURLs returns each user-specified webhook URL as provided
+// with secrets masked unless the user requested that secrets be shown.
+// Base64 encoded webhook URLs are masked entirely.
+func (c Config) redactedRawWebhookURLs() []string {
+	webhookURLs := make([]string, 0, len(c.webhookURLs))
+	for _, webhookURL := range c.webhookURLs {
+		if !c.ShowSecrets {
+			webhookURL = webhookurl.Redact(webhookURL)
+		}
+		webhookURLs = append(webhookURLs, webhookURL)
+	}
+
+	return webhookURLs
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// loadDestinations resolves the settings for every destination defined in
+// the configuration file so that each destination may be used by clients of
+// the serve command. Settings for each destination are applied on top of a
+// copy of the current settings, leaving the current settings unmodified, and
+// the webhook URLs for each destination are validated. The resolved
+// destinations are not modified after startup; MessageConfig returns a copy
+// for each message.
+func (c *Config) loadDestinations(fc fileConfig) error {
+	destinations := make(map[string]Config, len(fc.Destinations))
+
+	for _, name := range fc.destinationNames() {
+		dest := c.clone()
+		dest.Destination = name
+
+		if err := dest.applyFileSettings(fc.Destinations[name]); err != nil {
+			return fmt.Errorf(
+				"failed to apply settings for destination %q from configuration file: %w",
+				name,
+				err,
+			)
+		}
+
+		if len(dest.webhookURLs) == 0 {
+			return fmt.Errorf("webhook URL not specified for destination %q", name)
+		}
+
+		if err := dest.validateWebhookURLs(dest.DisableWebhookURLValidation); err != nil {
+			return fmt.Errorf("invalid settings for destination %q: %w", name, err)
+		}
+
+		destinations[name] = dest
+	}
+
+	c.destinations = destinations
+
+	return nil
+}
+
+// MessageConfig returns a copy of the configuration suitable for submitting
+// a single message to the specified destination. If a destination is not
+// specified the current settings are used. An error is returned if the
+// destination is not defined in the configuration file.
+func (c Config) MessageConfig(destination string) (Config, error) {
+	msgCfg := c
+
+	if destination != "" {
+		dest, ok := c.destinations[destination]
+		if !ok {
+			return Config{}, fmt.Errorf("%w: %q", ErrDestinationNotFound, destination)
+		}
+		msgCfg = dest
+	}
+
+	msgCfg = msgCfg.clone()
+	msgCfg.Command = ""
+	msgCfg.destinations = nil
+
+	return msgCfg, nil
+}
+
+// clone returns a copy of the configuration whose collections may be
+// modified without affecting the original configuration.
+func (c Config) clone() Config {
+	clone := c
+	clone.webhookURLs = slices.Clone(c.webhookURLs)
+	clone.Facts = slices.Clone(c.Facts)
+	clone.TargetURLs = slices.Clone(c.TargetURLs)
+	clone.UserMentions = slices.Clone(c.UserMentions)
+	clone.TemplateVars = maps.Clone(c.TemplateVars)
+	clone.valueSources = maps.Clone(c.valueSources)
+
+	return clone
+}
+
+// DestinationNames returns a sorted list of destinations available to
+// clients of the serve command.
+func (c Config) DestinationNames() []string {
+	return slices.Sorted(maps.Keys(c.destinations))
+}
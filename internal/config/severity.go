@@ -0,0 +1,62 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"strings"
+)
+
+// colorSeverities maps color names commonly used with the (formerly
+// supported) color flag to the equivalent severity level. Severity level
+// names are also accepted as color values.
+var colorSeverities = map[string]string{
+	SeverityInfo:     SeverityInfo,
+	SeverityOK:       SeverityOK,
+	SeverityWarning:  SeverityWarning,
+	SeverityCritical: SeverityCritical,
+	SeverityUnknown:  SeverityUnknown,
+	"blue":           SeverityInfo,
+	"green":          SeverityOK,
+	"yellow":         SeverityWarning,
+	"orange":         SeverityWarning,
+	"amber":          SeverityWarning,
+	"red":            SeverityCritical,
+	"gray":           SeverityUnknown,
+	"grey":           SeverityUnknown,
+}
+
+// supportedSeverities returns the list of supported severity levels.
+func supportedSeverities() []string {
+	return []string{
+		SeverityInfo,
+		SeverityOK,
+		SeverityWarning,
+		SeverityCritical,
+		SeverityUnknown,
+	}
+}
+
+// handleSeverity normalizes the user-specified severity level. If a severity
+// level was not specified, a recognized color name specified via the color
+// flag is used to select the equivalent severity level. Unrecognized color
+// values (e.g., hex codes) are ignored.
+func (c *Config) handleSeverity() {
+	c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+
+	if c.Severity != "" || c.ThemeColor == defaultMessageThemeColor {
+		return
+	}
+
+	severity, ok := colorSeverities[strings.ToLower(strings.TrimSpace(c.ThemeColor))]
+	if !ok {
+		return
+	}
+
+	c.Severity = severity
+	c.valueSources["severity"] = c.source("color")
+}
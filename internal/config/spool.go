@@ -0,0 +1,29 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+)
+
+// validateSpool asserts that the settings used by the flush command are
+// valid.
+func (c Config) validateSpool() error {
+	switch {
+	case c.SpoolDir == "":
+		return fmt.Errorf("spool directory not specified for the %s command", CommandFlush)
+
+	case c.SpoolMaxAge <= 0:
+		return fmt.Errorf("spool max age too short")
+
+	case c.SpoolMaxAttempts < 1:
+		return fmt.Errorf("spool max attempts too short")
+	}
+
+	return nil
+}
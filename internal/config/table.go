@@ -0,0 +1,170 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Supported formats for tabular data displayed as a table.
+const (
+	// TableFormatCSV is the comma-separated values format.
+	TableFormatCSV string = "csv"
+
+	// TableFormatTSV is the tab-separated values format.
+	TableFormatTSV string = "tsv"
+)
+
+// supportedTableFormats returns the list of supported table formats.
+func supportedTableFormats() []string {
+	return []string{
+		TableFormatCSV,
+		TableFormatTSV,
+	}
+}
+
+// tableFormat returns the format of the tabular data. If a format was not
+// specified, the tab-separated values format is assumed for files with a
+// .tsv or .tab extension or data whose first line contains tabs but no
+// commas. Otherwise the comma-separated values format is assumed.
+func (c Config) tableFormat(data []byte) string {
+	if c.TableFormat != "" {
+		return c.TableFormat
+	}
+
+	switch strings.ToLower(filepath.Ext(c.TableFile)) {
+	case ".tsv", ".tab":
+		return TableFormatTSV
+	case ".csv":
+		return TableFormatCSV
+	}
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.ContainsRune(firstLine, '\t') && !bytes.ContainsRune(firstLine, ',') {
+		return TableFormatTSV
+	}
+
+	return TableFormatCSV
+}
+
+// parseTable parses the given tabular data in the specified format. A
+// leading byte order mark is ignored and rows may have differing numbers of
+// fields. Leading and trailing whitespace is removed from each field.
+func parseTable(data []byte, format string) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	switch format {
+	case TableFormatTSV:
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+
+	// Leading whitespace is trimmed by the reader (rather than only
+	// afterwards) so that quoted fields preceded by a space are recognized.
+	// This is not done for TSV as the tab separator is itself whitespace;
+	// empty fields would be skipped.
+	default:
+		reader.TrimLeadingSpace = true
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+
+	return rows, nil
+}
+
+// handleTableInput populates the Table field using the user-specified table
+// file or standard input. An error is returned if standard input is also
+// used for other input, if reading the content fails or if the content is
+// not valid tabular data.
+func (c *Config) handleTableInput() error {
+	if c.TableFile == "" {
+		return nil
+	}
+
+	if c.TableFile == stdinFileName && (c.messageReadsStdin() || c.codeReadsStdin()) {
+		return fmt.Errorf(
+			"%w: standard input may not be used for both the table and the message, code block, payload, notification or template data",
+			ErrConflictingMessageSources,
+		)
+	}
+
+	if c.TableFormat != "" && !slices.Contains(supportedTableFormats(), c.TableFormat) {
+		return fmt.Errorf(
+			"unsupported table format %q; expected one of %q",
+			c.TableFormat,
+			supportedTableFormats(),
+		)
+	}
+
+	data, err := readInputFile(c.TableFile)
+	if err != nil {
+		return fmt.Errorf("failed to read table file: %w", err)
+	}
+
+	format := c.tableFormat(data)
+
+	rows, err := parseTable(data, format)
+	if err != nil {
+		return fmt.Errorf("failed to parse table file as %s: %w", strings.ToUpper(format), err)
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("table file %s contains no rows", c.TableFile)
+	}
+
+	c.Table = rows
+
+	return nil
+}
+
+// validateTable asserts that the settings used to display a table are
+// valid. Tables are only supported for messages generated from message
+// text.
+func (c Config) validateTable() error {
+	if c.TableFile == "" {
+		return nil
+	}
+
+	switch {
+	case c.Command != "":
+		return fmt.Errorf(
+			"unsupported: You cannot use the table-file option with the %s command",
+			c.Command,
+		)
+
+	case len(c.Payload) > 0:
+		return fmt.Errorf("unsupported: You cannot use the table-file option with a payload file")
+
+	case c.InputFormat != "":
+		return fmt.Errorf("unsupported: You cannot use the table-file option with an input format")
+
+	case c.TableMaxColumns < 1:
+		return fmt.Errorf("table max columns too short")
+
+	case c.TableMaxRows < 1:
+		return fmt.Errorf("table max rows too short")
+	}
+
+	return nil
+}
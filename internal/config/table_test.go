@@ -0,0 +1,165 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableFormat(t *testing.T) {
+	tests := map[string]struct {
+		format   string
+		file     string
+		data     string
+		expected string
+	}{
+		"format specified":           {format: TableFormatCSV, file: "data.tsv", data: "a\tb", expected: TableFormatCSV},
+		"tsv extension":              {file: "data.tsv", data: "a,b", expected: TableFormatTSV},
+		"tab extension":              {file: "data.TAB", data: "a,b", expected: TableFormatTSV},
+		"csv extension":              {file: "data.csv", data: "a\tb", expected: TableFormatCSV},
+		"tabs in first line":         {file: "-", data: "a\tb\nc,d", expected: TableFormatTSV},
+		"tabs and commas":            {file: "-", data: "a\tb,c", expected: TableFormatCSV},
+		"commas in first line":       {file: "data.txt", data: "a,b\nc\td", expected: TableFormatCSV},
+		"single column":              {file: "-", data: "a\nb", expected: TableFormatCSV},
+		"tabs after first line only": {file: "-", data: "a\nb\tc", expected: TableFormatCSV},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := Config{TableFormat: tt.format, TableFile: tt.file}
+			if got := c.tableFormat([]byte(tt.data)); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		format    string
+		expected  [][]string
+		expectErr bool
+	}{
+		"csv": {
+			data:     "name,status\nweb01, OK \n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"name", "status"}, {"web01", "OK"}},
+		},
+		"csv quoted fields": {
+			data:     "name,message\nweb01,\"disk full, 95%\"\n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"name", "message"}, {"web01", "disk full, 95%"}},
+		},
+		"tsv": {
+			data:     "name\tstatus\nweb01\tOK\n",
+			format:   TableFormatTSV,
+			expected: [][]string{{"name", "status"}, {"web01", "OK"}},
+		},
+		"tsv empty cells": {
+			data:     "name\tstatus\tusage\nweb01\t\t90%\n\t\tlast\n",
+			format:   TableFormatTSV,
+			expected: [][]string{{"name", "status", "usage"}, {"web01", "", "90%"}, {"", "", "last"}},
+		},
+		"tsv whitespace around fields": {
+			data:     "name\tstatus\n web01 \t OK\n",
+			format:   TableFormatTSV,
+			expected: [][]string{{"name", "status"}, {"web01", "OK"}},
+		},
+		"csv empty cells": {
+			data:     "name,status,usage\nweb01,,90%\nweb02, ,\n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"name", "status", "usage"}, {"web01", "", "90%"}, {"web02", "", ""}},
+		},
+		"csv quoted field after space": {
+			data:     "name, \"disk full, 95%\"\n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"name", "disk full, 95%"}},
+		},
+		"tsv with stray quotes": {
+			data:     "name\tmessage\nweb01\t5\" disk\n",
+			format:   TableFormatTSV,
+			expected: [][]string{{"name", "message"}, {"web01", "5\" disk"}},
+		},
+		"byte order mark": {
+			data:     "\ufeffname,status\n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"name", "status"}},
+		},
+		"ragged rows": {
+			data:     "a,b,c\nd\ne,f\n",
+			format:   TableFormatCSV,
+			expected: [][]string{{"a", "b", "c"}, {"d"}, {"e", "f"}},
+		},
+		"empty": {
+			data:     "",
+			format:   TableFormatCSV,
+			expected: nil,
+		},
+		"unterminated quote": {
+			data:      "a,\"b\n",
+			format:    TableFormatCSV,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseTable([]byte(tt.data), tt.format)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got %q; expected error", got)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case !reflect.DeepEqual(got, tt.expected):
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateTable(t *testing.T) {
+	tests := map[string]struct {
+		cfg       Config
+		expectErr bool
+	}{
+		"valid": {
+			cfg: Config{TableFile: "data.csv", TableMaxColumns: 1, TableMaxRows: 1},
+		},
+		"table not requested": {
+			cfg: Config{},
+		},
+		"max columns too short": {
+			cfg:       Config{TableFile: "data.csv", TableMaxColumns: 0, TableMaxRows: 1},
+			expectErr: true,
+		},
+		"max rows too short": {
+			cfg:       Config{TableFile: "data.csv", TableMaxColumns: 1, TableMaxRows: 0},
+			expectErr: true,
+		},
+		"input format": {
+			cfg:       Config{TableFile: "data.csv", TableMaxColumns: 1, TableMaxRows: 1, InputFormat: InputFormatGrafana},
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.cfg.validateTable()
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Errorf("got nil; expected error")
+			case !tt.expectErr && err != nil:
+				t.Errorf("got %v; expected no error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atc0005/send2teams/internal/msgtemplate"
+)
+
+// ErrTemplateRequired indicates that a template setting was specified, but a
+// message template was not.
+var ErrTemplateRequired = errors.New("message template required")
+
+// templateVarsStringFlag is the collection of user-specified template
+// variables indexed by name.
+type templateVarsStringFlag map[string]string
+
+// reset clears all previously specified template variables.
+func (tvs *templateVarsStringFlag) reset() {
+	*tvs = nil
+}
+
+// String returns a list of all user-specified template variables.
+func (tvs *templateVarsStringFlag) String() string {
+
+	// From the `flag` package docs:
+	// "The flag package may call the String method with a zero-valued
+	// receiver, such as a nil pointer."
+	if tvs == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(*tvs))
+	for k := range *tvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s=%s", k, (*tvs)[k]))
+	}
+
+	return strings.Join(items, ", ")
+}
+
+// Set is called once by the flag package, in command line order, for each
+// flag present. The value is split on the first equals sign in order to
+// specify the name and value for a template variable. An error is returned
+// if the name is empty. Specifying a variable more than once replaces the
+// earlier value.
+func (tvs *templateVarsStringFlag) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("template variable %q not in key=value format", value)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("template variable name must not be empty")
+	}
+
+	if *tvs == nil {
+		*tvs = make(templateVarsStringFlag)
+	}
+	(*tvs)[name] = val
+
+	return nil
+}
+
+// handleTemplate renders the message title and text from the user-specified
+// message template (if any). The rendered title is only used if the template
+// defines a title template and a title was not specified by the user.
+func (c *Config) handleTemplate() error {
+	if c.Template == "" && c.TemplateFile == "" {
+		if c.TemplateData != "" || len(c.TemplateVars) > 0 {
+			return fmt.Errorf(
+				"%w: template-data and var flags require template or template-file flags",
+				ErrTemplateRequired,
+			)
+		}
+
+		return nil
+	}
+
+	name := "template"
+	text := c.Template
+
+	if c.TemplateFile != "" {
+		// #nosec G304 -- file path is intentionally provided by the user
+		contents, err := os.ReadFile(c.TemplateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		name = c.TemplateFile
+		text = string(contents)
+	}
+
+	var jsonData []byte
+	if c.TemplateData != "" {
+		var err error
+		jsonData, err = readInputFile(c.TemplateData)
+		if err != nil {
+			return fmt.Errorf("failed to read template data: %w", err)
+		}
+	}
+
+	data, err := msgtemplate.NewData(c.TemplateVars, jsonData)
+	if err != nil {
+		return err
+	}
+
+	result, err := msgtemplate.Render(name, text, data)
+	if err != nil {
+		return err
+	}
+
+	c.MessageText = result.Text
+	c.valueSources["message"] = sourceTemplate
+
+	if result.HasTitle && !c.setByUser("title") {
+		c.MessageTitle = result.Title
+		c.valueSources["title"] = sourceTemplate
+	}
+
+	return nil
+}
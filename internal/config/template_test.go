@@ -0,0 +1,136 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHandleTemplate(t *testing.T) {
+	templateFile := writeConfigFile(t, "message.tmpl", `{{define "title"}}{{.Vars.status}}: {{.Vars.host}}{{end}}
+Disk usage on {{.Vars.host}} at {{index .Env "S2T_VAR_SITE"}} is {{.Data.usage}}.
+`)
+	dataFile := writeConfigFile(t, "data.json", `{"usage": "95%"}`)
+
+	tests := map[string]struct {
+		args           []string
+		env            map[string]string
+		expectedTitle  string
+		expectedText   string
+		expectedSource valueSource
+		expectedErr    error
+		expectErr      bool
+	}{
+		"template": {
+			args:           []string{"--template", "Hello {{.Vars.name}}", "--var", "name=world"},
+			expectedText:   "Hello world",
+			expectedSource: sourceDefault,
+		},
+		"template file with title": {
+			args: []string{
+				"--template-file", templateFile,
+				"--template-data", dataFile,
+				"--var", "host=web01",
+				"--var", "status=CRITICAL",
+			},
+			env:            map[string]string{"S2T_VAR_SITE": "hq"},
+			expectedTitle:  "CRITICAL: web01",
+			expectedText:   "Disk usage on web01 at hq is 95%.",
+			expectedSource: sourceTemplate,
+		},
+		"title flag over template title": {
+			args: []string{
+				"--template-file", templateFile,
+				"--template-data", dataFile,
+				"--var", "host=web01",
+				"--var", "status=CRITICAL",
+				"--title", "From flag",
+			},
+			env:            map[string]string{"S2T_VAR_SITE": "hq"},
+			expectedTitle:  "From flag",
+			expectedText:   "Disk usage on web01 at hq is 95%.",
+			expectedSource: sourceFlag,
+		},
+		"title environment variable over template title": {
+			args: []string{
+				"--template-file", templateFile,
+				"--template-data", dataFile,
+				"--var", "host=web01",
+				"--var", "status=CRITICAL",
+			},
+			env: map[string]string{
+				"S2T_VAR_SITE":     "hq",
+				"SEND2TEAMS_TITLE": "From env",
+			},
+			expectedTitle:  "From env",
+			expectedText:   "Disk usage on web01 at hq is 95%.",
+			expectedSource: sourceEnv,
+		},
+		"environment variable without prefix": {
+			args:      []string{"--template", `{{index .Env "SEND2TEAMS_TEAM"}}`},
+			env:       map[string]string{"SEND2TEAMS_TEAM": "Operations"},
+			expectErr: true,
+		},
+		"missing variable": {
+			args:      []string{"--template", "Hello {{.Vars.name}}"},
+			expectErr: true,
+		},
+		"variable without template": {
+			args:        []string{"--message", "test", "--var", "name=world"},
+			expectErr:   true,
+			expectedErr: ErrTemplateRequired,
+		},
+		"template data without template": {
+			args:        []string{"--message", "test", "--template-data", dataFile},
+			expectErr:   true,
+			expectedErr: ErrTemplateRequired,
+		},
+		"missing template file": {
+			args:      []string{"--template-file", templateFile + ".missing"},
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for envVar, value := range tt.env {
+				t.Setenv(envVar, value)
+			}
+
+			cfg, err := newTestConfig(t, append([]string{"--url", testWebhookURL}, tt.args...)...)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got nil; expected error")
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case tt.expectErr:
+				return
+			}
+
+			if cfg.MessageText != tt.expectedText {
+				t.Errorf("got text %q; expected %q", cfg.MessageText, tt.expectedText)
+			}
+
+			if cfg.MessageTitle != tt.expectedTitle {
+				t.Errorf("got title %q; expected %q", cfg.MessageTitle, tt.expectedTitle)
+			}
+
+			if got := cfg.source("title"); got != tt.expectedSource {
+				t.Errorf("got title source %q; expected %q", got, tt.expectedSource)
+			}
+
+			if got := cfg.source("message"); got != sourceTemplate {
+				t.Errorf("got message source %q; expected %q", got, sourceTemplate)
+			}
+		})
+	}
+}
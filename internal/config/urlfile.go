@@ -0,0 +1,166 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// credentialsDirectoryEnvVar is the environment variable set by systemd to
+// the directory containing the credentials (e.g., via LoadCredential=)
+// passed to a service.
+const credentialsDirectoryEnvVar string = "CREDENTIALS_DIRECTORY"
+
+// urlCredentialName is the name of the systemd credential containing the
+// target webhook URL.
+const urlCredentialName string = "send2teams-url"
+
+// maxSecretFileSize is the maximum size in bytes of a webhook URL file or
+// webhook URL key file.
+const maxSecretFileSize int64 = 64 * 1024
+
+// ErrConflictingURLSources indicates that the user specified both a webhook
+// URL and a webhook URL file.
+var ErrConflictingURLSources = errors.New("conflicting webhook URL sources specified")
+
+// ErrInsecureSecretFile indicates that a webhook URL file or webhook URL key
+// file is readable by group or other users.
+var ErrInsecureSecretFile = errors.New("file is readable by other users")
+
+// handleURLFile populates the webhook URLs using the user-specified webhook
+// URL file. If a webhook URL file is not specified and no webhook URL was
+// provided by any other source, the systemd credential named
+// send2teams-url is used if present. Webhook URLs from a webhook URL file
+// replace those from a configuration file. An error is returned if a
+// webhook URL was also specified via flag or environment variable or if
+// reading the file fails.
+func (c *Config) handleURLFile() error {
+	filename := c.URLFile
+
+	switch {
+	case filename != "":
+		if c.setByUser("url") {
+			return fmt.Errorf(
+				"%w: only one of url or url-file flags may be used",
+				ErrConflictingURLSources,
+			)
+		}
+
+	case len(c.webhookURLs) == 0 && os.Getenv(credentialsDirectoryEnvVar) != "":
+		credential := filepath.Join(os.Getenv(credentialsDirectoryEnvVar), urlCredentialName)
+
+		_, err := os.Stat(credential)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to access %s credential: %w", urlCredentialName, err)
+		}
+
+		filename = credential
+
+	default:
+		return nil
+	}
+
+	webhookURLs, err := readURLFile(filename, c.AllowInsecureURLFile)
+	if err != nil {
+		return err
+	}
+
+	c.webhookURLs.reset()
+	for _, webhookURL := range webhookURLs {
+		if err := c.webhookURLs.Set(webhookURL); err != nil {
+			return err
+		}
+	}
+	c.valueSources["url"] = sourceFile
+
+	return nil
+}
+
+// readURLFile reads the webhook URLs (one per line) from the specified file.
+// Empty lines and lines beginning with a # character are ignored. See
+// readSecretFile for the checks applied to the file.
+func readURLFile(filename string, allowInsecure bool) ([]string, error) {
+	data, err := readSecretFile(filename, "webhook URL file", allowInsecure)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhookURLs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		webhookURLs = append(webhookURLs, line)
+	}
+
+	if len(webhookURLs) == 0 {
+		return nil, fmt.Errorf("webhook URL file %q does not contain a webhook URL", filename)
+	}
+
+	return webhookURLs, nil
+}
+
+// readSecretFile reads the contents of the specified file containing a
+// secret (e.g., a webhook URL), described by the given description in
+// errors. Unless insecure files are allowed, an error is returned if the
+// file is readable by group or other users. File permissions are not
+// checked on Windows.
+func readSecretFile(filename string, description string, allowInsecure bool) ([]byte, error) {
+	// #nosec G304 -- file path is intentionally provided by the user
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", description, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", description, err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s %q is not a regular file", description, filename)
+	}
+
+	if !allowInsecure && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf(
+			"%w: %s %q has permissions %s; restrict access (e.g., chmod 600) or use the allow-insecure-url-file flag",
+			ErrInsecureSecretFile,
+			description,
+			filename,
+			info.Mode().Perm(),
+		)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSecretFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", description, err)
+	}
+
+	if int64(len(data)) > maxSecretFileSize {
+		return nil, fmt.Errorf(
+			"%s %q exceeds the maximum size of %d bytes",
+			description,
+			filename,
+			maxSecretFileSize,
+		)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingZabbixParameters indicates that Zabbix mode was requested, but
+// the expected Zabbix media type script parameters were not provided.
+var ErrMissingZabbixParameters = errors.New("required Zabbix parameters not provided")
+
+// defaultZabbixSender is the sender used for messages generated in Zabbix
+// mode if a sender is not specified.
+const defaultZabbixSender string = "Zabbix"
+
+// Names of the supported Zabbix media type script parameters. The first three
+// correspond to the {ALERT.SENDTO}, {ALERT.SUBJECT} and {ALERT.MESSAGE}
+// positional parameters. Names follow the conventions of the webhook media
+// types bundled with Zabbix.
+const (
+	zabbixParamSendTo            string = "alert_sendto"
+	zabbixParamSubject           string = "alert_subject"
+	zabbixParamMessage           string = "alert_message"
+	zabbixParamEventID           string = "event_id"
+	zabbixParamEventSeverity     string = "event_severity"
+	zabbixParamEventNSeverity    string = "event_nseverity"
+	zabbixParamEventValue        string = "event_value"
+	zabbixParamEventDate         string = "event_date"
+	zabbixParamEventTime         string = "event_time"
+	zabbixParamEventRecoveryID   string = "event_recovery_id"
+	zabbixParamEventRecoveryDate string = "event_recovery_date"
+	zabbixParamEventRecoveryTime string = "event_recovery_time"
+	zabbixParamEventDuration     string = "event_duration"
+	zabbixParamEventOpData       string = "event_opdata"
+	zabbixParamEventUpdate       string = "event_update_status"
+	zabbixParamTriggerID         string = "trigger_id"
+	zabbixParamTriggerStatus     string = "trigger_status"
+	zabbixParamHostName          string = "host_name"
+	zabbixParamHostIP            string = "host_ip"
+	zabbixParamZabbixURL         string = "zabbix_url"
+)
+
+// zabbixAlert represents the details of an alert provided to a Zabbix media
+// type script.
+type zabbixAlert map[string]string
+
+// parseZabbixArgs parses the parameters passed to a Zabbix media type
+// script. Parameters are either provided as a single JSON object or as the
+// {ALERT.SENDTO}, {ALERT.SUBJECT} and {ALERT.MESSAGE} positional parameters
+// followed by optional name=value pairs (e.g., event_id={EVENT.ID}).
+func parseZabbixArgs(args []string) (zabbixAlert, error) {
+	if len(args) == 1 && strings.HasPrefix(strings.TrimSpace(args[0]), "{") {
+		var params map[string]interface{}
+		if err := json.Unmarshal([]byte(args[0]), &params); err != nil {
+			return nil, fmt.Errorf("failed to parse Zabbix JSON parameters: %w", err)
+		}
+
+		alert := make(zabbixAlert, len(params))
+		for name, value := range params {
+			switch v := value.(type) {
+			case string:
+				alert[strings.ToLower(name)] = strings.TrimSpace(v)
+			case nil:
+			default:
+				alert[strings.ToLower(name)] = fmt.Sprint(v)
+			}
+		}
+
+		return alert, nil
+	}
+
+	if len(args) < 3 {
+		return nil, fmt.Errorf(
+			"%w: expected {ALERT.SENDTO}, {ALERT.SUBJECT} and {ALERT.MESSAGE} parameters or a JSON parameter set; got %d parameters",
+			ErrMissingZabbixParameters,
+			len(args),
+		)
+	}
+
+	alert := zabbixAlert{
+		zabbixParamSendTo:  strings.TrimSpace(args[0]),
+		zabbixParamSubject: strings.TrimSpace(args[1]),
+		zabbixParamMessage: strings.TrimSpace(args[2]),
+	}
+
+	for _, arg := range args[3:] {
+		name, value, found := strings.Cut(arg, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid Zabbix parameter %q; expected name=value", arg)
+		}
+		alert[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	return alert, nil
+}
+
+// param returns the value of the specified parameter. Macros which Zabbix
+// was unable to resolve (e.g., "{EVENT.RECOVERY.ID}" for problem events) are
+// treated as empty values.
+func (a zabbixAlert) param(name string) string {
+	value := a[name]
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		return ""
+	}
+
+	return value
+}
+
+// isRecovery indicates whether the alert is for a recovery event.
+func (a zabbixAlert) isRecovery() bool {
+	switch {
+	case a.param(zabbixParamEventValue) == "0":
+		return true
+	case strings.EqualFold(a.param(zabbixParamTriggerStatus), "OK"):
+		return true
+	case a.param(zabbixParamEventRecoveryID) != "":
+		return true
+	default:
+		return false
+	}
+}
+
+// isUpdate indicates whether the alert is for an update (e.g., an
+// acknowledgement) of a problem event.
+func (a zabbixAlert) isUpdate() bool {
+	return a.param(zabbixParamEventUpdate) == "1"
+}
+
+// status returns a description of the event status.
+func (a zabbixAlert) status() string {
+	switch {
+	case a.isRecovery():
+		return "RESOLVED"
+	case a.isUpdate():
+		return "UPDATED"
+	case a.param(zabbixParamTriggerStatus) != "":
+		return strings.ToUpper(a.param(zabbixParamTriggerStatus))
+	case a.param(zabbixParamEventValue) == "1":
+		return "PROBLEM"
+	default:
+		return ""
+	}
+}
+
+// facts returns a collection of key details for the alert. Details without a
+// value are omitted.
+func (a zabbixAlert) facts() []Fact {
+	started := strings.TrimSpace(
+		a.param(zabbixParamEventDate) + " " + a.param(zabbixParamEventTime),
+	)
+	recovered := strings.TrimSpace(
+		a.param(zabbixParamEventRecoveryDate) + " " + a.param(zabbixParamEventRecoveryTime),
+	)
+
+	host := a.param(zabbixParamHostName)
+	if ip := a.param(zabbixParamHostIP); host != "" && ip != "" && ip != host {
+		host = fmt.Sprintf("%s (%s)", host, ip)
+	}
+
+	candidates := []Fact{
+		{Title: "Status", Value: a.status()},
+		{Title: "Severity", Value: a.param(zabbixParamEventSeverity)},
+		{Title: "Host", Value: host},
+		{Title: "Event ID", Value: a.param(zabbixParamEventID)},
+		{Title: "Operational data", Value: a.param(zabbixParamEventOpData)},
+		{Title: "Started", Value: started},
+		{Title: "Recovered", Value: recovered},
+		{Title: "Duration", Value: a.param(zabbixParamEventDuration)},
+	}
+
+	facts := make([]Fact, 0, len(candidates))
+	for _, fact := range candidates {
+		if fact.Value != "" {
+			facts = append(facts, fact)
+		}
+	}
+
+	return facts
+}
+
+// severity returns the message severity for the alert based on the event
+// status and the numeric (or named) event severity.
+func (a zabbixAlert) severity() string {
+	switch {
+	case a.isRecovery():
+		return SeverityOK
+	case a.isUpdate():
+		return SeverityInfo
+	}
+
+	severity := a.param(zabbixParamEventNSeverity)
+	if severity == "" {
+		severity = strings.ToLower(a.param(zabbixParamEventSeverity))
+	}
+
+	switch severity {
+	case "1", "information":
+		return SeverityInfo
+	case "2", "3", "warning", "average":
+		return SeverityWarning
+	case "4", "5", "high", "disaster":
+		return SeverityCritical
+	default:
+		return SeverityUnknown
+	}
+}
+
+// targetURLs returns a target URL for the event in the Zabbix frontend if
+// the frontend URL, trigger ID and event ID are available.
+func (a zabbixAlert) targetURLs() []TargetURL {
+	frontendURL := a.param(zabbixParamZabbixURL)
+	triggerID := a.param(zabbixParamTriggerID)
+	eventID := a.param(zabbixParamEventID)
+
+	if frontendURL == "" || triggerID == "" || eventID == "" {
+		return nil
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(frontendURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil
+	}
+
+	u.Path += "/tr_events.php"
+	u.RawQuery = url.Values{
+		"triggerid": []string{triggerID},
+		"eventid":   []string{eventID},
+	}.Encode()
+
+	return []TargetURL{{URL: *u, Description: "View event"}}
+}
+
+// handleZabbixMode populates message details and (if not otherwise
+// specified) the webhook URL from Zabbix media type script parameters if
+// Zabbix mode was requested. Values specified by the user via flag or
+// environment variable are not overridden. The {ALERT.SENDTO} webhook URL
+// may be base64 encoded in the same way as a webhook URL specified via
+// flag. Requesting more than one notification mode is reported by Validate.
+func (c *Config) handleZabbixMode() error {
+	if !c.ZabbixMode || c.conflictingModes() {
+		return nil
+	}
+
+	alert, err := parseZabbixArgs(c.Args)
+	if err != nil {
+		return err
+	}
+
+	if sendTo := alert.param(zabbixParamSendTo); sendTo != "" && !c.setByUser("url") {
+		c.webhookURLs.reset()
+		if err := c.webhookURLs.Set(sendTo); err != nil {
+			return fmt.Errorf("invalid Zabbix {ALERT.SENDTO} parameter: %w", err)
+		}
+		c.valueSources["url"] = sourceZabbix
+	}
+
+	if !c.setByUser("title") {
+		c.MessageTitle = alert.param(zabbixParamSubject)
+	}
+
+	if !c.setByUser("message") {
+		c.MessageText = alert.param(zabbixParamMessage)
+	}
+
+	if !c.setByUser("sender") && c.Sender == "" {
+		c.Sender = defaultZabbixSender
+	}
+
+	if !c.setByUser("target-url") {
+		c.TargetURLs = append(c.TargetURLs, alert.targetURLs()...)
+	}
+
+	if !c.setByUser("severity") {
+		c.Severity = alert.severity()
+	}
+
+	// Facts specified by the user are listed after the alert facts.
+	c.Facts = append(alert.facts(), c.Facts...)
+
+	return nil
+}
@@ -0,0 +1,276 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseZabbixArgs(t *testing.T) {
+	tests := map[string]struct {
+		args        []string
+		expected    zabbixAlert
+		expectErr   bool
+		expectedErr error
+	}{
+		"positional parameters": {
+			args: []string{" https://example.com/hook ", "Problem: High CPU", "CPU is high\n"},
+			expected: zabbixAlert{
+				zabbixParamSendTo:  "https://example.com/hook",
+				zabbixParamSubject: "Problem: High CPU",
+				zabbixParamMessage: "CPU is high",
+			},
+		},
+		"positional parameters with name=value pairs": {
+			args: []string{"to", "subject", "message", "Event_ID=42", "event_opdata= load=5 ", "host_name="},
+			expected: zabbixAlert{
+				zabbixParamSendTo:      "to",
+				zabbixParamSubject:     "subject",
+				zabbixParamMessage:     "message",
+				zabbixParamEventID:     "42",
+				zabbixParamEventOpData: "load=5",
+				zabbixParamHostName:    "",
+			},
+		},
+		"JSON parameters": {
+			args: []string{`{"Alert_SendTo": "to", "alert_subject": " subject ", "event_nseverity": 4, "event_value": null}`},
+			expected: zabbixAlert{
+				zabbixParamSendTo:         "to",
+				zabbixParamSubject:        "subject",
+				zabbixParamEventNSeverity: "4",
+			},
+		},
+		"invalid JSON parameters": {
+			args:      []string{`{"alert_sendto": `},
+			expectErr: true,
+		},
+		"too few positional parameters": {
+			args:        []string{"to", "subject"},
+			expectErr:   true,
+			expectedErr: ErrMissingZabbixParameters,
+		},
+		"no parameters": {
+			args:        nil,
+			expectErr:   true,
+			expectedErr: ErrMissingZabbixParameters,
+		},
+		"invalid name=value pair": {
+			args:      []string{"to", "subject", "message", "event_id"},
+			expectErr: true,
+		},
+		"missing name": {
+			args:      []string{"to", "subject", "message", "=42"},
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseZabbixArgs(tt.args)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got %v; expected error", got)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case !tt.expectErr && !reflect.DeepEqual(got, tt.expected):
+				t.Errorf("got %v; expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestZabbixAlertSeverityAndStatus(t *testing.T) {
+	tests := map[string]struct {
+		alert            zabbixAlert
+		expectedSeverity string
+		expectedStatus   string
+	}{
+		"numeric severity": {
+			alert:            zabbixAlert{zabbixParamEventValue: "1", zabbixParamEventNSeverity: "4"},
+			expectedSeverity: SeverityCritical,
+			expectedStatus:   "PROBLEM",
+		},
+		"named severity": {
+			alert:            zabbixAlert{zabbixParamEventSeverity: "Average"},
+			expectedSeverity: SeverityWarning,
+		},
+		"numeric severity preferred": {
+			alert:            zabbixAlert{zabbixParamEventNSeverity: "1", zabbixParamEventSeverity: "Disaster"},
+			expectedSeverity: SeverityInfo,
+		},
+		"not classified": {
+			alert:            zabbixAlert{zabbixParamEventNSeverity: "0"},
+			expectedSeverity: SeverityUnknown,
+		},
+		"recovery by event value": {
+			alert:            zabbixAlert{zabbixParamEventValue: "0", zabbixParamEventNSeverity: "5"},
+			expectedSeverity: SeverityOK,
+			expectedStatus:   "RESOLVED",
+		},
+		"recovery by trigger status": {
+			alert:            zabbixAlert{zabbixParamTriggerStatus: "ok"},
+			expectedSeverity: SeverityOK,
+			expectedStatus:   "RESOLVED",
+		},
+		"recovery by recovery event ID": {
+			alert:            zabbixAlert{zabbixParamEventRecoveryID: "43"},
+			expectedSeverity: SeverityOK,
+			expectedStatus:   "RESOLVED",
+		},
+		"unresolved recovery event ID macro": {
+			alert:            zabbixAlert{zabbixParamEventRecoveryID: "{EVENT.RECOVERY.ID}", zabbixParamTriggerStatus: "PROBLEM", zabbixParamEventNSeverity: "2"},
+			expectedSeverity: SeverityWarning,
+			expectedStatus:   "PROBLEM",
+		},
+		"update": {
+			alert:            zabbixAlert{zabbixParamEventUpdate: "1", zabbixParamEventValue: "1", zabbixParamEventNSeverity: "5"},
+			expectedSeverity: SeverityInfo,
+			expectedStatus:   "UPDATED",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.alert.severity(); got != tt.expectedSeverity {
+				t.Errorf("got severity %q; expected %q", got, tt.expectedSeverity)
+			}
+
+			if got := tt.alert.status(); got != tt.expectedStatus {
+				t.Errorf("got status %q; expected %q", got, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestZabbixAlertFacts(t *testing.T) {
+	alert := zabbixAlert{
+		zabbixParamEventValue:    "1",
+		zabbixParamEventSeverity: "High",
+		zabbixParamHostName:      "web01",
+		zabbixParamHostIP:        "192.0.2.10",
+		zabbixParamEventID:       "42",
+		zabbixParamEventDate:     "2026.10.17",
+		zabbixParamEventTime:     "10:00:00",
+		zabbixParamEventOpData:   "{EVENT.OPDATA}",
+	}
+
+	expected := []Fact{
+		{Title: "Status", Value: "PROBLEM"},
+		{Title: "Severity", Value: "High"},
+		{Title: "Host", Value: "web01 (192.0.2.10)"},
+		{Title: "Event ID", Value: "42"},
+		{Title: "Started", Value: "2026.10.17 10:00:00"},
+	}
+
+	if got := alert.facts(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; expected %v", got, expected)
+	}
+}
+
+func TestZabbixAlertTargetURLs(t *testing.T) {
+	tests := map[string]struct {
+		alert    zabbixAlert
+		expected string
+	}{
+		"frontend URL": {
+			alert: zabbixAlert{
+				zabbixParamZabbixURL: "https://zabbix.example.com/",
+				zabbixParamTriggerID: "100",
+				zabbixParamEventID:   "42",
+			},
+			expected: "https://zabbix.example.com/tr_events.php?eventid=42&triggerid=100",
+		},
+		"frontend URL with path": {
+			alert: zabbixAlert{
+				zabbixParamZabbixURL: "https://example.com/zabbix",
+				zabbixParamTriggerID: "100",
+				zabbixParamEventID:   "42",
+			},
+			expected: "https://example.com/zabbix/tr_events.php?eventid=42&triggerid=100",
+		},
+		"missing event ID": {
+			alert: zabbixAlert{
+				zabbixParamZabbixURL: "https://zabbix.example.com",
+				zabbixParamTriggerID: "100",
+			},
+		},
+		"invalid frontend URL": {
+			alert: zabbixAlert{
+				zabbixParamZabbixURL: "zabbix.example.com",
+				zabbixParamTriggerID: "100",
+				zabbixParamEventID:   "42",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.alert.targetURLs()
+
+			switch {
+			case tt.expected == "" && got != nil:
+				t.Errorf("got %v; expected no target URLs", got)
+			case tt.expected != "" && len(got) != 1:
+				t.Errorf("got %v; expected a single target URL", got)
+			case tt.expected != "" && got[0].URL.String() != tt.expected:
+				t.Errorf("got %q; expected %q", got[0].URL.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleZabbixMode(t *testing.T) {
+	c := Config{
+		ZabbixMode: true,
+		Args: []string{
+			"https://example.com/hook",
+			"Problem: High CPU",
+			"CPU is high",
+			"event_value=1",
+			"event_nseverity=3",
+		},
+		MessageTitle: "user title",
+		Facts:        factsStringFlag{{Title: "Team", Value: "ops"}},
+		valueSources: map[string]valueSource{
+			"title": sourceFlag,
+		},
+	}
+
+	if err := c.handleZabbixMode(); err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if c.MessageTitle != "user title" {
+		t.Errorf("got title %q; expected title set by user to be retained", c.MessageTitle)
+	}
+
+	if c.MessageText != "CPU is high" {
+		t.Errorf("got message %q; expected %q", c.MessageText, "CPU is high")
+	}
+
+	if c.Severity != SeverityWarning {
+		t.Errorf("got severity %q; expected %q", c.Severity, SeverityWarning)
+	}
+
+	if c.Sender != defaultZabbixSender {
+		t.Errorf("got sender %q; expected %q", c.Sender, defaultZabbixSender)
+	}
+
+	if got := []string(c.webhookURLs); !reflect.DeepEqual(got, []string{"https://example.com/hook"}) {
+		t.Errorf("got webhook URLs %q; expected {ALERT.SENDTO} value", got)
+	}
+
+	if last := c.Facts[len(c.Facts)-1]; last.Title != "Team" {
+		t.Errorf("got last fact %v; expected facts set by user to follow alert facts", last)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/atc0005/send2teams/internal/alertmanager"
+)
+
+// ErrNoAlerts indicates that a notification does not contain any alerts.
+var ErrNoAlerts = errors.New("notification does not contain any alerts")
+
+// Supported alert and notification status values. Grafana uses the same
+// values as Alertmanager.
+const (
+	StatusFiring   string = alertmanager.StatusFiring
+	StatusResolved string = alertmanager.StatusResolved
+)
+
+// Alert is a single alert within a Grafana notification. The alert extends
+// the Alertmanager alert with Grafana specific fields.
+type Alert struct {
+	alertmanager.Alert
+
+	Values       map[string]float64 `json:"values"`
+	ValueString  string             `json:"valueString"`
+	SilenceURL   string             `json:"silenceURL"`
+	DashboardURL string             `json:"dashboardURL"`
+	PanelURL     string             `json:"panelURL"`
+	ImageURL     string             `json:"imageURL"`
+}
+
+// Notification is the payload of a Grafana alerting webhook notification.
+// The payload extends the Alertmanager webhook payload with Grafana specific
+// fields.
+//
+// https://grafana.com/docs/grafana/latest/alerting/configure-notifications/manage-contact-points/integrations/webhook-notifier/
+type Notification struct {
+	Receiver          string          `json:"receiver"`
+	Status            string          `json:"status"`
+	OrgID             int             `json:"orgId"`
+	Alerts            []Alert         `json:"alerts"`
+	GroupLabels       alertmanager.KV `json:"groupLabels"`
+	CommonLabels      alertmanager.KV `json:"commonLabels"`
+	CommonAnnotations alertmanager.KV `json:"commonAnnotations"`
+	ExternalURL       string          `json:"externalURL"`
+	Version           string          `json:"version"`
+	GroupKey          string          `json:"groupKey"`
+	TruncatedAlerts   int             `json:"truncatedAlerts"`
+	Title             string          `json:"title"`
+	State             string          `json:"state"`
+	Message           string          `json:"message"`
+}
+
+// Parse decodes the given Grafana webhook payload. An error is returned if
+// the payload is invalid or does not contain any alerts.
+func Parse(data []byte) (Notification, error) {
+	var n Notification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return Notification{}, fmt.Errorf("failed to parse Grafana notification: %w", err)
+	}
+
+	if len(n.Alerts) == 0 {
+		return Notification{}, ErrNoAlerts
+	}
+
+	return n, nil
+}
+
+// Firing returns the alerts which are currently firing.
+func (n Notification) Firing() []Alert {
+	alerts := make([]Alert, 0, len(n.Alerts))
+	for _, alert := range n.Alerts {
+		if alert.Status == StatusFiring {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts
+}
+
+// UniqueLabels returns the labels for the given alert which are not common
+// to every alert in the notification.
+func (n Notification) UniqueLabels(alert Alert) alertmanager.KV {
+	return alert.Labels.Without(n.CommonLabels)
+}
+
+// FormattedValues returns the values of the alert query and expressions
+// formatted for display, sorted by reference ID (e.g., A, B, C).
+func (a Alert) FormattedValues() [][2]string {
+	refIDs := make([]string, 0, len(a.Values))
+	for refID := range a.Values {
+		refIDs = append(refIDs, refID)
+	}
+	slices.Sort(refIDs)
+
+	values := make([][2]string, 0, len(refIDs))
+	for _, refID := range refIDs {
+		values = append(values, [2]string{
+			refID,
+			strconv.FormatFloat(a.Values[refID], 'g', -1, 64),
+		})
+	}
+
+	return values
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package grafana
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/atc0005/send2teams/internal/alertmanager"
+)
+
+const testNotification string = `{
+	"receiver": "teams",
+	"status": "firing",
+	"orgId": 1,
+	"groupLabels": {"alertname": "HighCPU"},
+	"commonLabels": {"alertname": "HighCPU", "team": "ops"},
+	"commonAnnotations": {},
+	"externalURL": "https://grafana.example.com/",
+	"version": "1",
+	"title": "[FIRING:1] HighCPU",
+	"state": "alerting",
+	"message": "CPU usage is high",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighCPU", "team": "ops", "instance": "web01"},
+			"annotations": {"summary": "CPU on web01"},
+			"startsAt": "2026-10-17T12:00:00Z",
+			"endsAt": "0001-01-01T00:00:00Z",
+			"values": {"B": 95.5, "A": 0.955},
+			"valueString": "[ var='A' value=0.955 ], [ var='B' value=95.5 ]",
+			"generatorURL": "https://grafana.example.com/alerting/grafana/abc/view",
+			"fingerprint": "c6eadffa33fcdf37",
+			"silenceURL": "https://grafana.example.com/alerting/silence/new",
+			"dashboardURL": "https://grafana.example.com/d/abc",
+			"panelURL": "https://grafana.example.com/d/abc?viewPanel=2",
+			"imageURL": "https://grafana.example.com/render/abc.png"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "HighCPU", "team": "ops", "instance": "web02"},
+			"annotations": {"description": "CPU back to normal"}
+		}
+	]
+}`
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		payload        string
+		expectedAlerts int
+		expectedErr    error
+		expectErr      bool
+	}{
+		"valid": {
+			payload:        testNotification,
+			expectedAlerts: 2,
+		},
+		"no alerts": {
+			payload:     `{"status": "firing", "alerts": []}`,
+			expectErr:   true,
+			expectedErr: ErrNoAlerts,
+		},
+		"invalid JSON": {
+			payload:   `{"status": "firing", "alerts": `,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse([]byte(tt.payload))
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Fatalf("got %+v; expected error", n)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			case len(n.Alerts) != tt.expectedAlerts:
+				t.Errorf("got %d alerts; expected %d", len(n.Alerts), tt.expectedAlerts)
+			}
+		})
+	}
+}
+
+func TestParseAlertFields(t *testing.T) {
+	n, err := Parse([]byte(testNotification))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	alert := n.Alerts[0]
+
+	switch {
+	case n.Title != "[FIRING:1] HighCPU" || n.Message != "CPU usage is high":
+		t.Errorf("got title %q and message %q; expected Grafana notification fields", n.Title, n.Message)
+	case alert.Name() != "HighCPU":
+		t.Errorf("got name %q; expected %q", alert.Name(), "HighCPU")
+	case alert.Summary() != "CPU on web01":
+		t.Errorf("got summary %q; expected %q", alert.Summary(), "CPU on web01")
+	case !alert.StartsAt.Equal(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)):
+		t.Errorf("got start time %v; expected 2026-10-17 12:00:00 UTC", alert.StartsAt)
+	case alert.GeneratorURL != "https://grafana.example.com/alerting/grafana/abc/view":
+		t.Errorf("got generator URL %q; expected Alertmanager alert fields", alert.GeneratorURL)
+	case alert.DashboardURL != "https://grafana.example.com/d/abc" || alert.ImageURL == "":
+		t.Errorf("got %+v; expected Grafana alert fields", alert)
+	case n.Alerts[1].Summary() != "CPU back to normal":
+		t.Errorf("got summary %q; expected description fallback", n.Alerts[1].Summary())
+	}
+}
+
+func TestFiring(t *testing.T) {
+	n, err := Parse([]byte(testNotification))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	firing := n.Firing()
+	if len(firing) != 1 || firing[0].Labels["instance"] != "web01" {
+		t.Errorf("got %+v; expected only the web01 alert", firing)
+	}
+
+	n.Alerts[0].Status = StatusResolved
+	if firing := n.Firing(); len(firing) != 0 {
+		t.Errorf("got %+v; expected no firing alerts", firing)
+	}
+}
+
+func TestUniqueLabels(t *testing.T) {
+	n, err := Parse([]byte(testNotification))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	expected := alertmanager.KV{"instance": "web01"}
+	if got := n.UniqueLabels(n.Alerts[0]); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; expected %v", got, expected)
+	}
+}
+
+func TestFormattedValues(t *testing.T) {
+	tests := map[string]struct {
+		values   map[string]float64
+		expected [][2]string
+	}{
+		"sorted by reference ID": {
+			values: map[string]float64{"C": 3, "A": 1, "B": 2},
+			expected: [][2]string{
+				{"A", "1"},
+				{"B", "2"},
+				{"C", "3"},
+			},
+		},
+		"fractional and large values": {
+			values: map[string]float64{"B": 95.5, "A": 0.955, "D": 1e21, "C": -2.25},
+			expected: [][2]string{
+				{"A", "0.955"},
+				{"B", "95.5"},
+				{"C", "-2.25"},
+				{"D", "1e+21"},
+			},
+		},
+		"multi-character reference IDs": {
+			values: map[string]float64{"query": 1, "B": 2, "A1": 3},
+			expected: [][2]string{
+				{"A1", "3"},
+				{"B", "2"},
+				{"query", "1"},
+			},
+		},
+		"no values": {
+			expected: [][2]string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			alert := Alert{Values: tt.values}
+
+			if got := alert.FormattedValues(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v; expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package msgtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// TitleTemplateName is the name of the (optional) template used to render
+// the message title. The title template is defined within the message
+// template using a {{define "title"}} action.
+const TitleTemplateName string = "title"
+
+// EnvPrefix is the prefix of the environment variables made available to
+// message templates. Other environment variables (e.g., the SEND2TEAMS_URL
+// environment variable used to specify the webhook URL) are not exposed so
+// that a template cannot include secrets in a message.
+const EnvPrefix string = "S2T_VAR_"
+
+// truncationSuffix is appended to values shortened by the truncate template
+// function.
+const truncationSuffix string = "…"
+
+// markdownEscaper escapes characters with special meaning in the Markdown
+// subset supported by Microsoft Teams.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"#", `\#`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+	">", `\>`,
+	"|", `\|`,
+)
+
+// Data is the data made available to message templates.
+type Data struct {
+	// Vars is the collection of user-specified template variables.
+	Vars map[string]string
+
+	// Env is the collection of environment variables whose names begin with
+	// EnvPrefix, indexed by full name.
+	Env map[string]string
+
+	// Data is the (optional) user-specified JSON data.
+	Data interface{}
+}
+
+// Result is the output from rendering a message template.
+type Result struct {
+	// Title is the rendered message title. This is empty if the template
+	// does not define a title template.
+	Title string
+
+	// Text is the rendered message text.
+	Text string
+
+	// HasTitle indicates whether the template defines a title template.
+	HasTitle bool
+}
+
+// NewData creates template data using the given template variables, the
+// environment variables whose names begin with EnvPrefix and (if provided)
+// the given JSON data.
+func NewData(vars map[string]string, jsonData []byte) (Data, error) {
+	data := Data{
+		Vars: make(map[string]string, len(vars)),
+		Env:  make(map[string]string),
+	}
+
+	for k, v := range vars {
+		data.Vars[k] = v
+	}
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, EnvPrefix) {
+			data.Env[k] = v
+		}
+	}
+
+	if len(bytes.TrimSpace(jsonData)) > 0 {
+		if err := json.Unmarshal(jsonData, &data.Data); err != nil {
+			return Data{}, fmt.Errorf("failed to parse template data: %w", err)
+		}
+	}
+
+	return data, nil
+}
+
+// Funcs returns the helper functions made available to message templates.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"truncate":       Truncate,
+		"escapeMarkdown": EscapeMarkdown,
+		"now":            time.Now,
+		"timestamp":      timestamp,
+		"formatTime":     formatTime,
+		"default":        defaultValue,
+		"upper":          strings.ToUpper,
+		"lower":          strings.ToLower,
+		"trim":           strings.TrimSpace,
+	}
+}
+
+// Render parses and executes the given message template using the provided
+// data. If the template defines a title template, the title is also
+// rendered. Leading and trailing whitespace is removed from the rendered
+// title and text. Referencing a missing variable, environment variable or
+// JSON data key is an error (e.g., so that a misspelled variable name is
+// not rendered as "<no value>"); the index function may be used for
+// optional values.
+func Render(name string, text string, data Data) (Result, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse message template: %w", err)
+	}
+
+	var result Result
+
+	var textBuf bytes.Buffer
+	if err := tmpl.Execute(&textBuf, data); err != nil {
+		return Result{}, fmt.Errorf("failed to render message text: %w", err)
+	}
+	result.Text = strings.TrimSpace(textBuf.String())
+
+	if titleTmpl := tmpl.Lookup(TitleTemplateName); titleTmpl != nil {
+		var titleBuf bytes.Buffer
+		if err := titleTmpl.Execute(&titleBuf, data); err != nil {
+			return Result{}, fmt.Errorf("failed to render message title: %w", err)
+		}
+		result.Title = strings.TrimSpace(titleBuf.String())
+		result.HasTitle = true
+	}
+
+	return result, nil
+}
+
+// Truncate shortens the given value to at most length characters, replacing
+// the final character with an ellipsis if the value was shortened.
+func Truncate(length int, s string) string {
+	if length <= 0 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return string(runes[:length-1]) + truncationSuffix
+}
+
+// EscapeMarkdown escapes characters in the given value which would otherwise
+// be interpreted as Markdown formatting.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// timestamp returns the current time formatted using RFC 3339.
+func timestamp() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+// formatTime formats the given time value using the specified layout. The
+// time value may be a time.Time, a Unix timestamp in seconds or a string in
+// RFC 3339 format.
+func formatTime(layout string, t interface{}) (string, error) {
+	switch v := t.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case int:
+		return time.Unix(int64(v), 0).Format(layout), nil
+	case int64:
+		return time.Unix(v, 0).Format(layout), nil
+	case float64:
+		return time.Unix(int64(v), 0).Format(layout), nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse time value %q: %w", v, err)
+		}
+
+		return parsed.Format(layout), nil
+	default:
+		return "", fmt.Errorf("unsupported time value %v of type %T", t, t)
+	}
+}
+
+// defaultValue returns the given value, or the specified default if the
+// given value is empty.
+func defaultValue(def string, value interface{}) string {
+	if value == nil {
+		return def
+	}
+
+	s := fmt.Sprint(value)
+	if s == "" {
+		return def
+	}
+
+	return s
+}
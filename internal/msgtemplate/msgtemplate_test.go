@@ -0,0 +1,277 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package msgtemplate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := map[string]struct {
+		length   int
+		value    string
+		expected string
+	}{
+		"zero length": {
+			length:   0,
+			value:    "hello",
+			expected: "",
+		},
+		"negative length": {
+			length:   -1,
+			value:    "hello",
+			expected: "",
+		},
+		"shorter than length": {
+			length:   10,
+			value:    "hello",
+			expected: "hello",
+		},
+		"exact length": {
+			length:   5,
+			value:    "hello",
+			expected: "hello",
+		},
+		"longer than length": {
+			length:   4,
+			value:    "hello",
+			expected: "hel…",
+		},
+		"length of one": {
+			length:   1,
+			value:    "hello",
+			expected: "…",
+		},
+		"multi-byte exact length": {
+			length:   3,
+			value:    "日本語",
+			expected: "日本語",
+		},
+		"multi-byte longer than length": {
+			length:   5,
+			value:    "héllo wörld",
+			expected: "héll…",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Truncate(tt.length, tt.value); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected string
+	}{
+		"plain text": {
+			value:    "disk usage 95%",
+			expected: "disk usage 95%",
+		},
+		"emphasis": {
+			value:    "*bold* _italic_ ~strike~",
+			expected: `\*bold\* \_italic\_ \~strike\~`,
+		},
+		"link": {
+			value:    "[name](https://example.com)",
+			expected: `\[name\]\(https://example.com\)`,
+		},
+		"heading, quote and table": {
+			value:    "# a > b | c",
+			expected: `\# a \> b \| c`,
+		},
+		"code and backslash": {
+			value:    "`C:\\temp`",
+			expected: "\\`C:\\\\temp\\`",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := EscapeMarkdown(tt.value); got != tt.expected {
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	const layout string = time.RFC3339
+
+	unix := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		layout    string
+		value     interface{}
+		expected  string
+		expectErr bool
+	}{
+		"time": {
+			layout:   layout,
+			value:    unix,
+			expected: unix.Format(layout),
+		},
+		"int": {
+			layout:   layout,
+			value:    1700000000,
+			expected: unix.Format(layout),
+		},
+		"int64": {
+			layout:   layout,
+			value:    int64(1700000000),
+			expected: unix.Format(layout),
+		},
+		"float64": {
+			layout:   layout,
+			value:    float64(1700000000),
+			expected: unix.Format(layout),
+		},
+		"RFC 3339 string": {
+			layout:   "2006-01-02 15:04",
+			value:    "2026-10-17T12:30:00Z",
+			expected: "2026-10-17 12:30",
+		},
+		"invalid string": {
+			layout:    layout,
+			value:     "yesterday",
+			expectErr: true,
+		},
+		"unsupported type": {
+			layout:    layout,
+			value:     true,
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := formatTime(tt.layout, tt.value)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Errorf("got %q; expected error", got)
+			case !tt.expectErr && err != nil:
+				t.Errorf("got %v; expected no error", err)
+			case got != tt.expected:
+				t.Errorf("got %q; expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewData(t *testing.T) {
+	t.Setenv(EnvPrefix+"SITE", "hq")
+	t.Setenv("SEND2TEAMS_URL", "https://example.com/secret")
+
+	vars := map[string]string{"host": "web01"}
+
+	data, err := NewData(vars, []byte(`{"status": "OK", "count": 2}`))
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	vars["host"] = "modified"
+
+	switch {
+	case data.Vars["host"] != "web01":
+		t.Errorf("got %q; expected template variables to be copied", data.Vars["host"])
+	case data.Env[EnvPrefix+"SITE"] != "hq":
+		t.Errorf("got %v; expected %sSITE environment variable", data.Env, EnvPrefix)
+	case data.Env["SEND2TEAMS_URL"] != "":
+		t.Errorf("got %v; expected environment variables without prefix to be excluded", data.Env)
+	}
+
+	expectedData := map[string]interface{}{"status": "OK", "count": float64(2)}
+	if !reflect.DeepEqual(data.Data, expectedData) {
+		t.Errorf("got %v; expected %v", data.Data, expectedData)
+	}
+
+	if data, err := NewData(nil, []byte("  \n")); err != nil || data.Data != nil {
+		t.Errorf("got %v, %v; expected no data and no error for blank JSON", data.Data, err)
+	}
+
+	if _, err := NewData(nil, []byte(`{"status": `)); err == nil {
+		t.Errorf("got nil; expected error for invalid JSON")
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := Data{
+		Vars: map[string]string{"host": "web01", "status": "CRITICAL"},
+		Env:  map[string]string{EnvPrefix + "SITE": "hq"},
+		Data: map[string]interface{}{"usage": "95%"},
+	}
+
+	tests := map[string]struct {
+		text      string
+		expected  Result
+		expectErr bool
+	}{
+		"text only": {
+			text:     "\n  {{.Vars.host}} is {{.Vars.status | lower}}  \n",
+			expected: Result{Text: "web01 is critical"},
+		},
+		"environment and JSON data": {
+			text:     "{{index .Env \"S2T_VAR_SITE\"}}: {{.Data.usage}}",
+			expected: Result{Text: "hq: 95%"},
+		},
+		"title template": {
+			text: `{{define "title"}} {{.Vars.status}}: {{.Vars.host}} {{end}}Disk usage on {{.Vars.host}}`,
+			expected: Result{
+				Title:    "CRITICAL: web01",
+				Text:     "Disk usage on web01",
+				HasTitle: true,
+			},
+		},
+		"empty title template": {
+			text:     `{{define "title"}}{{end}}body`,
+			expected: Result{Text: "body", HasTitle: true},
+		},
+		"optional variable": {
+			text:     `{{index .Vars "missing" | default "n/a"}}`,
+			expected: Result{Text: "n/a"},
+		},
+		"missing variable": {
+			text:      "{{.Vars.hots}}",
+			expectErr: true,
+		},
+		"missing JSON data key": {
+			text:      "{{.Data.useage}}",
+			expectErr: true,
+		},
+		"missing variable in title": {
+			text:      `{{define "title"}}{{.Vars.hots}}{{end}}body`,
+			expectErr: true,
+		},
+		"parse error": {
+			text:      "{{.Vars.host",
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render("test", tt.text, data)
+
+			switch {
+			case tt.expectErr && err == nil:
+				t.Errorf("got %+v; expected error", got)
+			case !tt.expectErr && err != nil:
+				t.Errorf("got %v; expected no error", err)
+			case got != tt.expected:
+				t.Errorf("got %+v; expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package webhookurl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix is the prefix identifying an encrypted webhook URL. The
+// prefix notes the format version so that the format may be changed without
+// breaking existing encrypted webhook URLs.
+const EncryptedPrefix string = "s2t-enc-v1:"
+
+// KeySize is the size in bytes of the (AES-256) key used to encrypt webhook
+// URLs.
+const KeySize int = 32
+
+// ErrInvalidKey indicates that a webhook URL encryption key is invalid.
+var ErrInvalidKey = errors.New("invalid webhook URL encryption key")
+
+// ErrDecryptionFailed indicates that an encrypted webhook URL could not be
+// decrypted; the value is corrupt, incomplete or was encrypted using a
+// different key.
+var ErrDecryptionFailed = errors.New("failed to decrypt webhook URL")
+
+// IsEncrypted indicates whether the given string is an encrypted webhook URL
+// (or the first of multiple comma separated segments of one).
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), EncryptedPrefix)
+}
+
+// ParseKey parses a webhook URL encryption key from the given data (e.g.,
+// the contents of a key file). The key is expected to be composed of KeySize
+// random bytes encoded using any of the base64 encoding formats supported by
+// DecodeBase64. Surrounding whitespace is ignored.
+func ParseKey(data []byte) ([]byte, error) {
+	key, err := decodeBase64(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: key is not base64 encoded: %w", ErrInvalidKey, err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf(
+			"%w: key is %d bytes, expected %d bytes",
+			ErrInvalidKey,
+			len(key),
+			KeySize,
+		)
+	}
+
+	return key, nil
+}
+
+// Encrypt encrypts the given webhook URL using AES-256-GCM authenticated
+// encryption with the given key. The result is composed of EncryptedPrefix
+// followed by the random nonce and ciphertext encoded using unpadded
+// alternate base64 encoding (base64url).
+//
+// The result may be split into multiple segments at any point after the
+// prefix; the segments are recombined by Decrypt when separated by commas.
+func Encrypt(webhookURL string, key []byte) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(strings.TrimSpace(webhookURL)), []byte(EncryptedPrefix))
+
+	return EncryptedPrefix + EncodeToBase64String(sealed), nil
+}
+
+// Decrypt decrypts the given encrypted webhook URL using the given key. The
+// encrypted webhook URL may be composed of multiple segments separated by
+// commas. An error is returned if the given string is not an encrypted
+// webhook URL or if it cannot be decrypted and authenticated using the key.
+func Decrypt(input string, key []byte) (string, error) {
+	input = strings.TrimSpace(input)
+	if !IsEncrypted(input) {
+		return "", fmt.Errorf("%w: missing %q prefix", ErrDecryptionFailed, EncryptedPrefix)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := strings.TrimPrefix(input, EncryptedPrefix)
+	encoded = strings.Join(strings.FieldsFunc(encoded, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	}), "")
+
+	sealed, err := decodeBase64(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid encoding: %w", ErrDecryptionFailed, err)
+	}
+
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return "", fmt.Errorf("%w: value is too short", ErrDecryptionFailed)
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w: wrong key or corrupt value", ErrDecryptionFailed)
+	}
+
+	return string(plaintext), nil
+}
+
+// newAEAD returns the AES-256-GCM cipher used to encrypt and decrypt webhook
+// URLs with the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf(
+			"%w: key is %d bytes, expected %d bytes",
+			ErrInvalidKey,
+			len(key),
+			KeySize,
+		)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package webhookurl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testWebhookURL string = "https://example.environment.api.powerplatform.com:443/powerautomate/automations/direct/workflows/ed3386c459104b11bd4e891c76e5e2a1/triggers/manual/paths/invoke?api-version=1&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=vqF0En-Z0ucuRTM01o2GuhMH3hKKkN2bOmlM31zaA"
+
+// testKey returns a key composed of KeySize bytes of the given value.
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	key := testKey(1)
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"webhook URL": {
+			input:    testWebhookURL,
+			expected: testWebhookURL,
+		},
+		"surrounding whitespace is removed": {
+			input:    " " + testWebhookURL + "\n",
+			expected: testWebhookURL,
+		},
+		"empty value": {
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := Encrypt(tt.input, key)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if !IsEncrypted(encrypted) {
+				t.Fatalf("got %q; expected %q prefix", encrypted, EncryptedPrefix)
+			}
+
+			if strings.Contains(encrypted, "sig=") {
+				t.Fatalf("got %q; expected plaintext to be absent", encrypted)
+			}
+
+			decrypted, err := Decrypt(encrypted, key)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if decrypted != tt.expected {
+				t.Errorf("got %q; expected %q", decrypted, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	key := testKey(1)
+
+	first, err := Encrypt(testWebhookURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	second, err := Encrypt(testWebhookURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	if first == second {
+		t.Errorf("got identical values %q; expected each encryption to differ", first)
+	}
+}
+
+func TestDecryptSegments(t *testing.T) {
+	key := testKey(1)
+
+	encrypted, err := Encrypt(testWebhookURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	split := len(EncryptedPrefix) + 10
+
+	tests := map[string]string{
+		"comma separated":           encrypted[:split] + "," + encrypted[split:],
+		"comma and space separated": encrypted[:split] + ", " + encrypted[split:],
+		"newline separated":         encrypted[:split] + "\n" + encrypted[split:],
+		"trailing newline":          encrypted + "\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := Decrypt(input, key)
+			if err != nil {
+				t.Fatalf("got %v; expected no error", err)
+			}
+
+			if decrypted != testWebhookURL {
+				t.Errorf("got %q; expected %q", decrypted, testWebhookURL)
+			}
+		})
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	key := testKey(1)
+
+	encrypted, err := Encrypt(testWebhookURL, key)
+	if err != nil {
+		t.Fatalf("got %v; expected no error", err)
+	}
+
+	payload := strings.TrimPrefix(encrypted, EncryptedPrefix)
+
+	// Flip a character in the middle of the ciphertext.
+	tampered := []byte(payload)
+	mid := len(tampered) / 2
+	if tampered[mid] == 'A' {
+		tampered[mid] = 'B'
+	} else {
+		tampered[mid] = 'A'
+	}
+
+	tests := map[string]struct {
+		input       string
+		key         []byte
+		expectedErr error
+	}{
+		"wrong key": {
+			input:       encrypted,
+			key:         testKey(2),
+			expectedErr: ErrDecryptionFailed,
+		},
+		"tampered ciphertext": {
+			input:       EncryptedPrefix + string(tampered),
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"truncated ciphertext": {
+			input:       encrypted[:len(encrypted)-4],
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"missing segment": {
+			input:       EncryptedPrefix + payload[20:],
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"too short": {
+			input:       EncryptedPrefix + "AAAA",
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"invalid encoding": {
+			input:       EncryptedPrefix + "not*base64",
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"missing prefix": {
+			input:       payload,
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"unsupported prefix version": {
+			input:       "s2t-enc-v2:" + payload,
+			key:         key,
+			expectedErr: ErrDecryptionFailed,
+		},
+		"short key": {
+			input:       encrypted,
+			key:         key[:16],
+			expectedErr: ErrInvalidKey,
+		},
+		"missing key": {
+			input:       encrypted,
+			key:         nil,
+			expectedErr: ErrInvalidKey,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := Decrypt(tt.input, tt.key)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("got %v; expected error %q", err, tt.expectedErr)
+			}
+
+			if decrypted != "" {
+				t.Errorf("got %q; expected empty result", decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptInvalidKey(t *testing.T) {
+	for _, size := range []int{0, 16, 24, 31, 33} {
+		if _, err := Encrypt(testWebhookURL, make([]byte, size)); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("key size %d: got %v; expected error %q", size, err, ErrInvalidKey)
+		}
+	}
+}
+
+func TestIsEncrypted(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected bool
+	}{
+		"encrypted":                  {input: EncryptedPrefix + "abc", expected: true},
+		"encrypted with whitespace":  {input: "  " + EncryptedPrefix + "abc", expected: true},
+		"prefix only":                {input: EncryptedPrefix, expected: true},
+		"webhook URL":                {input: testWebhookURL, expected: false},
+		"base64 webhook URL":         {input: base64.StdEncoding.EncodeToString([]byte(testWebhookURL)), expected: false},
+		"prefix not at start":        {input: "x" + EncryptedPrefix, expected: false},
+		"different version":          {input: "s2t-enc-v2:abc", expected: false},
+		"uppercase prefix":           {input: strings.ToUpper(EncryptedPrefix) + "abc", expected: false},
+		"empty":                      {input: "", expected: false},
+		"later segment of encrypted": {input: "abc," + EncryptedPrefix, expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsEncrypted(tt.input); got != tt.expected {
+				t.Errorf("got %t; expected %t", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := testKey(7)
+
+	tests := map[string]struct {
+		input       string
+		expectErr   bool
+		expectedKey []byte
+	}{
+		"standard encoding": {
+			input:       base64.StdEncoding.EncodeToString(key),
+			expectedKey: key,
+		},
+		"standard encoding with newline (openssl rand -base64)": {
+			input:       base64.StdEncoding.EncodeToString(key) + "\n",
+			expectedKey: key,
+		},
+		"unpadded url encoding": {
+			input:       base64.RawURLEncoding.EncodeToString(key),
+			expectedKey: key,
+		},
+		"too short": {
+			input:     base64.StdEncoding.EncodeToString(key[:16]),
+			expectErr: true,
+		},
+		"too long": {
+			input:     base64.StdEncoding.EncodeToString(append(key, 1)),
+			expectErr: true,
+		},
+		"not base64": {
+			input:     strings.Repeat("*", 44),
+			expectErr: true,
+		},
+		"empty": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseKey([]byte(tt.input))
+
+			switch {
+			case tt.expectErr && !errors.Is(err, ErrInvalidKey):
+				t.Fatalf("got %v; expected error %q", err, ErrInvalidKey)
+			case !tt.expectErr && err != nil:
+				t.Fatalf("got %v; expected no error", err)
+			case !bytes.Equal(got, tt.expectedKey):
+				t.Errorf("got key %x; expected %x", got, tt.expectedKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Adam Chalkley
+//
+// https://github.com/atc0005/send2teams
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package webhookurl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Redacted is the placeholder used in place of secret values removed from
+// webhook URLs.
+const Redacted string = "REDACTED"
+
+// pathTokenMinLength is the minimum length of a webhook URL path segment
+// treated as a secret token. Path segments which are shorter (or which
+// contain no digits) are assumed to be fixed path components such as
+// "workflows" or "IncomingWebhook".
+const pathTokenMinLength int = 16
+
+// pathTokenPrefixLength is the number of leading characters of a secret path
+// token retained when redacted so that webhook URLs may still be told apart.
+const pathTokenPrefixLength int = 4
+
+// secretQueryParams are the (lowercase) names of webhook URL query
+// parameters whose values are secret. The sig parameter holds the signature
+// which authorizes use of a Power Automate workflow URL.
+var secretQueryParams = []string{
+	"sig",
+	"signature",
+	"code",
+	"key",
+	"token",
+	"access_token",
+}
+
+// urlPattern matches URLs embedded in text such as error messages. Quotes
+// and whitespace terminate a match so that quoted URLs are handled.
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// Redact returns the given webhook URL with secrets masked. The values of
+// secret query parameters (e.g., the sig signature of Power Automate
+// workflow URLs) and passwords are replaced, while path tokens (e.g.,
+// workflow IDs) are replaced apart from a short prefix. The scheme, host and
+// remaining path and query are retained to aid troubleshooting.
+//
+// Values which are not URLs (e.g., base64 encoded webhook URLs) are replaced
+// entirely.
+func Redact(webhookURL string) string {
+	u, err := url.Parse(strings.TrimSpace(webhookURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Redacted
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), Redacted)
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if isPathToken(segment) {
+			segments[i] = segment[:pathTokenPrefixLength] + "..."
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+
+	u.RawQuery = redactQuery(u.RawQuery)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String()
+}
+
+// RedactText returns the given text with secrets masked in each embedded
+// URL. See Redact for details.
+func RedactText(text string) string {
+	return urlPattern.ReplaceAllStringFunc(text, Redact)
+}
+
+// RedactError returns an error whose message is the message of the given
+// error with secrets masked in each embedded URL. The given error remains
+// available via errors.Is and errors.As. A nil error is returned as-is.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return redactedError{err: err}
+}
+
+// redactedError is an error whose message has secrets masked.
+type redactedError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error with secrets masked.
+func (re redactedError) Error() string {
+	return RedactText(re.err.Error())
+}
+
+// Unwrap returns the wrapped error.
+func (re redactedError) Unwrap() error {
+	return re.err
+}
+
+// redactQuery masks the values of secret parameters in the given raw query
+// string. The order and encoding of all other parameters is retained.
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		rawName, _, hasValue := strings.Cut(param, "=")
+		if !hasValue {
+			continue
+		}
+
+		name := rawName
+		if unescaped, err := url.QueryUnescape(rawName); err == nil {
+			name = unescaped
+		}
+
+		for _, secret := range secretQueryParams {
+			if strings.EqualFold(name, secret) {
+				params[i] = rawName + "=" + Redacted
+
+				break
+			}
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// isPathToken indicates whether the given webhook URL path segment appears
+// to be a secret token such as a workflow ID or connector GUID.
+func isPathToken(segment string) bool {
+	if len(segment) < pathTokenMinLength {
+		return false
+	}
+
+	return strings.ContainsFunc(segment, unicode.IsDigit)
+}
@@ -58,6 +58,18 @@ func EncodeToBase64String(input []byte) string {
 	return base64.RawURLEncoding.EncodeToString(input)
 }
 
+// base64Encodings are the supported base64 encoding formats as defined in
+// RFC 4648 in the order in which decoding is attempted.
+var base64Encodings = []struct {
+	name     string
+	encoding *base64.Encoding
+}{
+	{name: "standard", encoding: base64.StdEncoding},
+	{name: "standard raw (unpadded)", encoding: base64.RawStdEncoding},
+	{name: "alternate (base64url)", encoding: base64.URLEncoding},
+	{name: "alternate raw (unpadded base64url)", encoding: base64.RawURLEncoding},
+}
+
 // decodeBase64 decodes base64 strings originally generated using any of the
 // supported base64 encoding formats as defined in RFC 4648:
 //
@@ -68,19 +80,33 @@ func EncodeToBase64String(input []byte) string {
 func decodeBase64(input string) ([]byte, error) {
 	input = strings.TrimSpace(input)
 
-	if data, err := base64.StdEncoding.DecodeString(input); err == nil {
-		return data, nil
+	var err error
+	for _, enc := range base64Encodings {
+		var data []byte
+		if data, err = enc.encoding.DecodeString(input); err == nil {
+			return data, nil
+		}
 	}
 
-	if data, err := base64.RawStdEncoding.DecodeString(input); err == nil {
-		return data, nil
-	}
+	return nil, err
+}
+
+// Base64Variants returns the names of the supported RFC 4648 base64 encoding
+// formats which successfully decode the given (single) base64 string in the
+// order in which decoding is attempted by DecodeBase64; the first format is
+// the one used. An empty list is returned if the string is not valid using
+// any supported format. Surrounding whitespace is ignored.
+func Base64Variants(input string) []string {
+	input = strings.TrimSpace(input)
 
-	if data, err := base64.URLEncoding.DecodeString(input); err == nil {
-		return data, nil
+	var variants []string
+	for _, enc := range base64Encodings {
+		if _, err := enc.encoding.DecodeString(input); err == nil {
+			variants = append(variants, enc.name)
+		}
 	}
 
-	return base64.RawURLEncoding.DecodeString(input)
+	return variants
 }
 
 // JoinBase64Segments safely joins one or more base64 encoded strings together